@@ -0,0 +1,76 @@
+package chess_test
+
+import (
+	"testing"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+)
+
+func TestCastling(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		fen   string
+		from  string
+		moves string
+	}{
+		{"both sides", "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "e1", "e1c1 e1d1 e1d2 e1e2 e1f1 e1f2 e1g1"},
+		{"black both sides", "r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 0 1", "e8", "e8c8 e8d7 e8d8 e8e7 e8f7 e8f8 e8g8"},
+		{"no rights", "r3k2r/8/8/8/8/8/8/R3K2R w kq - 0 1", "e1", "e1d1 e1d2 e1e2 e1f1 e1f2"},
+		{"kingside only", "r3k2r/8/8/8/8/8/8/R3K2R w K - 0 1", "e1", "e1d1 e1d2 e1e2 e1f1 e1f2 e1g1"},
+		{"blocked", "r3k2r/8/8/8/8/8/8/RN2K1NR w KQkq - 0 1", "e1", "e1d1 e1d2 e1e2 e1f1 e1f2"},
+		// b1 is only passed by the rook, so it can be attacked
+		{"rook's path attacked", "1r2k3/8/8/8/8/8/8/R3K3 w Q - 0 1", "e1", "e1c1 e1d1 e1d2 e1e2 e1f1 e1f2"},
+		{"out of check", "4k3/8/8/8/8/8/8/R3K2r w Q - 0 1", "e1", "e1d2 e1e2 e1f2"},
+		{"through check", "3rk3/8/8/8/8/8/8/R3K2R w KQ - 0 1", "e1", "e1e2 e1f1 e1f2 e1g1"},
+		{"into check", "2r1k3/8/8/8/8/8/8/R3K2R w KQ - 0 1", "e1", "e1d1 e1d2 e1e2 e1f1 e1f2 e1g1"},
+		{"through a knight's check", "4k3/8/8/8/8/4n3/8/R3K2R w KQ - 0 1", "e1", "e1d2 e1e2 e1f2"},
+		{"into a pawn's check", "4k3/8/8/8/8/8/7p/R3K2R w KQ - 0 1", "e1", "e1c1 e1d1 e1d2 e1e2 e1f1 e1f2"},
+	} {
+		p := mustParseFEN(t, tc.fen)
+		if moves := movesFrom(t, p, tc.from); moves != tc.moves {
+			t.Errorf("%s: %s has %q, want %q", tc.name, tc.from, moves, tc.moves)
+		}
+	}
+}
+
+func TestCastlingMovesRook(t *testing.T) {
+	for _, tc := range []struct {
+		uci, rookFrom, rookTo string
+	}{{"e1g1", "h1", "f1"}, {"e1c1", "a1", "d1"}} {
+		p := mustParseFEN(t, "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1")
+		mustPlay(t, p, tc.uci)
+		if piece := p.PieceAt(mustParseSquare(t, tc.rookTo)); piece != (chess.Piece{Color: chess.White, Type: chess.Rook}) {
+			t.Errorf("%s left %v on %s", tc.uci, piece, tc.rookTo)
+		}
+		if piece := p.PieceAt(mustParseSquare(t, tc.rookFrom)); !piece.IsEmpty() {
+			t.Errorf("%s left %v on %s", tc.uci, piece, tc.rookFrom)
+		}
+		if p.Castling != chess.BlackKingside|chess.BlackQueenside {
+			t.Errorf("castling rights %v after %s, want only black's", p.Castling, tc.uci)
+		}
+	}
+}
+
+func TestCastlingRights(t *testing.T) {
+	const fen = "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1"
+	for _, tc := range []struct {
+		name  string
+		moves []string
+		want  chess.CastlingRights
+	}{
+		{"king moves", []string{"e1e2"}, chess.BlackKingside | chess.BlackQueenside},
+		{"kingside rook moves", []string{"h1h2"}, chess.WhiteQueenside | chess.BlackKingside | chess.BlackQueenside},
+		{"queenside rook moves", []string{"a1a2"}, chess.WhiteKingside | chess.BlackKingside | chess.BlackQueenside},
+		{"rook moves back", []string{"h1h2", "a8a7", "h2h1"}, chess.WhiteQueenside | chess.BlackKingside},
+		{"rook taken", []string{"a1a8"}, chess.WhiteKingside | chess.BlackKingside},
+		{"rooks take each other", []string{"h1h8", "e8e7", "a1a8"}, chess.NoCastling},
+	} {
+		p := mustParseFEN(t, fen)
+		for _, move := range tc.moves {
+			mustPlay(t, p, move)
+		}
+		if p.Castling != tc.want {
+			t.Errorf("%s: castling rights %v, want %v", tc.name, p.Castling, tc.want)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package chess
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type DelayMode string
+
+const (
+	// increment is added after every move
+	DelayFischer DelayMode = "fischer"
+	// after a move, time used is given back up to the increment
+	DelayBronstein DelayMode = "bronstein"
+	// the clock waits for the increment to pass each move before counting down
+	DelaySimple DelayMode = "simple"
+)
+
+func ParseDelayMode(mode string) (DelayMode, error) {
+	switch DelayMode(mode) {
+	case DelayFischer, DelayBronstein, DelaySimple:
+		return DelayMode(mode), nil
+	}
+	return "", fmt.Errorf("unknown delay mode %s", mode)
+}
+
+// a zero base means the game isn't timed
+type TimeControl struct {
+	Base      time.Duration
+	Increment time.Duration
+	Delay     DelayMode
+}
+
+var Untimed = TimeControl{}
+
+// the time controls offered without having to type one in
+var TimeControlPresets = []string{"1+0", "2+1", "3+0"}
+
+func (t TimeControl) IsTimed() bool {
+	return t.Base > 0
+}
+
+// minutes+seconds, same as pgn's TimeControl header uses seconds+seconds
+func (t TimeControl) String() string {
+	if !t.IsTimed() {
+		return "-"
+	}
+	return strconv.FormatFloat(t.Base.Minutes(), 'f', -1, 64) + "+" +
+		strconv.FormatFloat(t.Increment.Seconds(), 'f', -1, 64)
+}
+
+// "minutes+seconds" e.g. "3+2", or "none" for no clock
+func ParseTimeControl(control string, delay DelayMode) (TimeControl, error) {
+	if control == "none" {
+		return Untimed, nil
+	}
+	parts := strings.Split(control, "+")
+	if len(parts) != 2 {
+		return Untimed, fmt.Errorf("time control %s should look like minutes+seconds", control)
+	}
+	minutes, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil || minutes <= 0 {
+		return Untimed, fmt.Errorf("bad base time in time control %s", control)
+	}
+	seconds, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil || seconds < 0 {
+		return Untimed, fmt.Errorf("bad increment in time control %s", control)
+	}
+	return TimeControl{
+		Base:      time.Duration(minutes * float64(time.Minute)),
+		Increment: time.Duration(seconds * float64(time.Second)),
+		Delay:     delay,
+	}, nil
+}
+
+// one clock per side, only the side that's running loses time
+type Clock struct {
+	Control   TimeControl
+	remaining [2]time.Duration
+	running   Color
+	// time the running side has spent on the current move
+	moveElapsed time.Duration
+	stopped     bool
+}
+
+// a clock at one moment, to be put back later with Restore
+type ClockState struct {
+	remaining   [2]time.Duration
+	running     Color
+	moveElapsed time.Duration
+	stopped     bool
+}
+
+func NewClock(control TimeControl, toMove Color) *Clock {
+	return &Clock{
+		Control:   control,
+		remaining: [2]time.Duration{control.Base, control.Base},
+		running:   toMove,
+	}
+}
+
+func (c *Clock) Remaining(color Color) time.Duration {
+	return c.remaining[color]
+}
+
+func (c *Clock) Running() Color {
+	return c.running
+}
+
+// advances the running side's clock, true if that ran it out
+func (c *Clock) Tick(dt time.Duration) bool {
+	if c.stopped || !c.Control.IsTimed() {
+		return false
+	}
+	c.moveElapsed += dt
+	if c.Control.Delay == DelaySimple {
+		// only whatever's gone past the delay comes off the clock
+		over := c.moveElapsed - c.Control.Increment
+		if over <= 0 {
+			return false
+		}
+		if over < dt {
+			dt = over
+		}
+	}
+	c.remaining[c.running] -= dt
+	if c.remaining[c.running] <= 0 {
+		c.remaining[c.running] = 0
+		c.stopped = true
+		return true
+	}
+	return false
+}
+
+// the mover finishing their move, starts the opponent's clock
+func (c *Clock) Press(mover Color) {
+	if c.stopped || !c.Control.IsTimed() {
+		return
+	}
+	switch c.Control.Delay {
+	case DelayFischer:
+		c.remaining[mover] += c.Control.Increment
+	case DelayBronstein:
+		if c.moveElapsed < c.Control.Increment {
+			c.remaining[mover] += c.moveElapsed
+		} else {
+			c.remaining[mover] += c.Control.Increment
+		}
+	}
+	c.running = mover.Other()
+	c.moveElapsed = 0
+}
+
+func (c *Clock) Stop() {
+	c.stopped = true
+}
+
+// flagged side, if any
+func (c *Clock) Flagged() (Color, bool) {
+	if c.Control.IsTimed() && c.remaining[c.running] <= 0 {
+		return c.running, true
+	}
+	return White, false
+}
+
+// takes time straight off a side, e.g. as a penalty. true if that flagged them
+func (c *Clock) Deduct(color Color, amount time.Duration) bool {
+	if c.stopped || !c.Control.IsTimed() {
+		return false
+	}
+	c.remaining[color] -= amount
+	if c.remaining[color] <= 0 {
+		c.remaining[color] = 0
+		c.running = color
+		c.stopped = true
+		return true
+	}
+	return false
+}
+
+func (c *Clock) State() ClockState {
+	return ClockState{c.remaining, c.running, c.moveElapsed, c.stopped}
+}
+
+func (c *Clock) Restore(state ClockState) {
+	c.remaining = state.remaining
+	c.running = state.running
+	c.moveElapsed = state.moveElapsed
+	c.stopped = state.stopped
+}
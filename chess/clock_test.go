@@ -0,0 +1,202 @@
+package chess_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+)
+
+func TestParseTimeControl(t *testing.T) {
+	for _, tc := range []struct {
+		text      string
+		base      time.Duration
+		increment time.Duration
+		printed   string
+	}{
+		{"1+0", time.Minute, 0, "1+0"},
+		{"2+1", 2 * time.Minute, time.Second, "2+1"},
+		{"3+0", 3 * time.Minute, 0, "3+0"},
+		{"0.5+0", 30 * time.Second, 0, "0.5+0"},
+		{"1+0.5", time.Minute, 500 * time.Millisecond, "1+0.5"},
+		{"2.25+1.75", 135 * time.Second, 1750 * time.Millisecond, "2.25+1.75"},
+		{"none", 0, 0, "-"},
+	} {
+		control, err := chess.ParseTimeControl(tc.text, chess.DelayFischer)
+		if err != nil {
+			t.Errorf("%s: %s", tc.text, err)
+			continue
+		}
+		if control.Base != tc.base || control.Increment != tc.increment {
+			t.Errorf("%s parsed to %v+%v, want %v+%v", tc.text, control.Base, control.Increment, tc.base, tc.increment)
+		}
+		if printed := control.String(); printed != tc.printed {
+			t.Errorf("%s printed as %s, want %s", tc.text, printed, tc.printed)
+		}
+	}
+
+	for _, text := range []string{"", "3", "3+", "+2", "3+2+1", "0+1", "-1+0", "3+-1", "a+b"} {
+		if control, err := chess.ParseTimeControl(text, chess.DelayFischer); err == nil {
+			t.Errorf("%q parsed to %s, want an error", text, control)
+		}
+	}
+
+	for _, preset := range chess.TimeControlPresets {
+		if _, err := chess.ParseTimeControl(preset, chess.DelayFischer); err != nil {
+			t.Errorf("preset %s: %s", preset, err)
+		}
+	}
+}
+
+func TestParseDelayMode(t *testing.T) {
+	for _, mode := range []chess.DelayMode{chess.DelayFischer, chess.DelayBronstein, chess.DelaySimple} {
+		if parsed, err := chess.ParseDelayMode(string(mode)); err != nil || parsed != mode {
+			t.Errorf("%s parsed to %s, %v", mode, parsed, err)
+		}
+	}
+	if _, err := chess.ParseDelayMode("hourglass"); err == nil {
+		t.Error("hourglass parsed without an error")
+	}
+}
+
+func newClock(delay chess.DelayMode) *chess.Clock {
+	return chess.NewClock(chess.TimeControl{Base: time.Minute, Increment: 2 * time.Second, Delay: delay}, chess.White)
+}
+
+// white spends the given time and moves
+func spend(c *chess.Clock, d time.Duration) {
+	c.Tick(d)
+	c.Press(chess.White)
+}
+
+func TestClockModes(t *testing.T) {
+	for _, tc := range []struct {
+		delay chess.DelayMode
+		spent time.Duration
+		left  time.Duration
+	}{
+		// the increment's added whatever the move took
+		{chess.DelayFischer, 5 * time.Second, 57 * time.Second},
+		{chess.DelayFischer, time.Second, 61 * time.Second},
+		// time used comes back, but never more than the increment
+		{chess.DelayBronstein, 5 * time.Second, 57 * time.Second},
+		{chess.DelayBronstein, time.Second, time.Minute},
+		// nothing comes off until the delay's used up
+		{chess.DelaySimple, 5 * time.Second, 57 * time.Second},
+		{chess.DelaySimple, time.Second, time.Minute},
+	} {
+		c := newClock(tc.delay)
+		spend(c, tc.spent)
+		if left := c.Remaining(chess.White); left != tc.left {
+			t.Errorf("%s: %v left after a %v move, want %v", tc.delay, left, tc.spent, tc.left)
+		}
+		if c.Remaining(chess.Black) != time.Minute {
+			t.Errorf("%s: black's clock ran during white's move", tc.delay)
+		}
+		if c.Running() != chess.Black {
+			t.Errorf("%s: %s's clock is running after white moved", tc.delay, c.Running())
+		}
+	}
+}
+
+// ticks that go over the delay only lose the part past it, and the delay starts again every move
+func TestSimpleDelayAcrossTicks(t *testing.T) {
+	c := newClock(chess.DelaySimple)
+	for i := 0; i < 3; i++ {
+		c.Tick(750 * time.Millisecond)
+	}
+	if left := c.Remaining(chess.White); left != time.Minute-250*time.Millisecond {
+		t.Errorf("%v left after 2.25s with a 2s delay", left)
+	}
+	c.Press(chess.White)
+	c.Tick(time.Second)
+	c.Press(chess.Black)
+	c.Tick(1500 * time.Millisecond)
+	if left := c.Remaining(chess.White); left != time.Minute-250*time.Millisecond {
+		t.Errorf("%v left after a second move inside the delay", left)
+	}
+}
+
+func TestClockFlag(t *testing.T) {
+	c := newClock(chess.DelayFischer)
+	if c.Tick(59 * time.Second) {
+		t.Fatal("flagged with a second left")
+	}
+	if _, flagged := c.Flagged(); flagged {
+		t.Fatal("flagged with a second left")
+	}
+	if !c.Tick(2 * time.Second) {
+		t.Fatal("didn't flag after running out")
+	}
+	if color, flagged := c.Flagged(); !flagged || color != chess.White {
+		t.Errorf("flagged is %s %v, want white", color, flagged)
+	}
+	if c.Remaining(chess.White) != 0 {
+		t.Errorf("%v left after flagging, want 0", c.Remaining(chess.White))
+	}
+
+	// a flagged clock stays stopped
+	c.Press(chess.White)
+	c.Tick(time.Second)
+	if c.Running() != chess.White || c.Remaining(chess.Black) != time.Minute || c.Remaining(chess.White) != 0 {
+		t.Error("clock kept going after a flag")
+	}
+}
+
+func TestClockDeduct(t *testing.T) {
+	c := newClock(chess.DelayFischer)
+	if c.Deduct(chess.Black, 10*time.Second) {
+		t.Fatal("flagged black with 50s left")
+	}
+	if left := c.Remaining(chess.Black); left != 50*time.Second {
+		t.Errorf("black has %v after losing 10s, want 50s", left)
+	}
+	if c.Running() != chess.White {
+		t.Errorf("%s's clock is running after black lost time", c.Running())
+	}
+	if !c.Deduct(chess.Black, time.Minute) {
+		t.Fatal("didn't flag black after taking more than they had")
+	}
+	if color, flagged := c.Flagged(); !flagged || color != chess.Black {
+		t.Errorf("flagged is %s %v, want black", color, flagged)
+	}
+	if c.Deduct(chess.White, time.Hour) {
+		t.Error("a stopped clock lost time")
+	}
+}
+
+func TestClockStopAndUntimed(t *testing.T) {
+	c := newClock(chess.DelayFischer)
+	c.Stop()
+	if c.Tick(time.Hour) || c.Remaining(chess.White) != time.Minute {
+		t.Error("a stopped clock ran")
+	}
+
+	c = chess.NewClock(chess.Untimed, chess.White)
+	if c.Tick(time.Hour) || c.Deduct(chess.White, time.Hour) {
+		t.Error("an untimed clock flagged")
+	}
+	if _, flagged := c.Flagged(); flagged {
+		t.Error("an untimed clock flagged")
+	}
+}
+
+func TestClockRestore(t *testing.T) {
+	c := newClock(chess.DelaySimple)
+	c.Tick(3 * time.Second)
+	state := c.State()
+
+	c.Press(chess.White)
+	c.Tick(10 * time.Second)
+	c.Deduct(chess.White, time.Hour)
+
+	c.Restore(state)
+	if c.Running() != chess.White || c.Remaining(chess.White) != 59*time.Second || c.Remaining(chess.Black) != time.Minute {
+		t.Fatalf("restored to %s running, %v and %v left", c.Running(), c.Remaining(chess.White), c.Remaining(chess.Black))
+	}
+	// the time already spent on the move comes back too, so the delay's still used up
+	c.Tick(time.Second)
+	if left := c.Remaining(chess.White); left != 58*time.Second {
+		t.Errorf("%v left a second after restoring, want 58s", left)
+	}
+}
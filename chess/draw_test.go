@@ -0,0 +1,142 @@
+package chess_test
+
+import (
+	"testing"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+	"github.com/val-is/bullet-hell-chess/notation"
+)
+
+func mustPlayGame(t *testing.T, g *chess.Game, moves ...string) {
+	t.Helper()
+	for _, uci := range moves {
+		move, err := notation.ParseUCIMove(g.Position, uci)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := g.Play(move); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRepetition(t *testing.T) {
+	g := chess.NewGame(chess.NewStartingPosition())
+	shuffle := []string{"g1f3", "g8f6", "f3g1", "f6g8"}
+
+	mustPlayGame(t, g, shuffle...)
+	if n := g.Repetitions(); n != 2 {
+		t.Errorf("start position seen %d times after one shuffle, want 2", n)
+	}
+	if outcome := g.Outcome(); outcome.IsOver() {
+		t.Errorf("%s after the second time", outcome)
+	}
+
+	mustPlayGame(t, g, shuffle...)
+	if n := g.Repetitions(); n != 3 {
+		t.Errorf("start position seen %d times after two shuffles, want 3", n)
+	}
+	if outcome := g.Outcome(); outcome != (chess.Outcome{Result: chess.ResultDraw, Termination: chess.TerminationRepetition}) {
+		t.Errorf("%s after the third time, want a draw by repetition", outcome)
+	}
+}
+
+// the same squares with a different side to move, or different rights, isn't the same position
+func TestRepetitionNeedsSamePosition(t *testing.T) {
+	// the rooks going out and back loses castling, so the first position never comes up again
+	g := chess.NewGame(mustParseFEN(t, "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1"))
+	for i := 0; i < 3; i++ {
+		mustPlayGame(t, g, "h1h2", "h8h7", "h2h1", "h7h8")
+	}
+	if n := g.Repetitions(); n != 3 {
+		t.Errorf("position after the rooks came back seen %d times, want 3", n)
+	}
+	mustPlayGame(t, g, "a1a2")
+	if outcome := g.Outcome(); outcome.IsOver() {
+		t.Errorf("%s once the position changed", outcome)
+	}
+
+	// white passing leaves the same board with black to move, which hasn't happened before
+	g = chess.NewGame(chess.NewStartingPosition())
+	mustPlayGame(t, g, "g1f3", "g8f6", "f3g1", "f6g8")
+	g.Pass()
+	if n := g.Repetitions(); n != 1 {
+		t.Errorf("start position with black to move seen %d times, want 1", n)
+	}
+}
+
+func TestFiftyMoveRule(t *testing.T) {
+	g := chess.NewGame(mustParseFEN(t, "4k3/8/8/8/8/8/4P3/R3K3 w - - 97 80"))
+	mustPlayGame(t, g, "a1a2")
+	if outcome := g.Outcome(); outcome.IsOver() {
+		t.Errorf("%s at 98 halfmoves", outcome)
+	}
+	mustPlayGame(t, g, "e8d8")
+	if outcome := g.Outcome(); outcome.IsOver() {
+		t.Errorf("%s at 99 halfmoves", outcome)
+	}
+	mustPlayGame(t, g, "a2a3")
+	if outcome := g.Outcome(); outcome != (chess.Outcome{Result: chess.ResultDraw, Termination: chess.TerminationFiftyMoves}) {
+		t.Errorf("%s at 100 halfmoves, want a draw by the fifty-move rule", outcome)
+	}
+
+	// a pawn move starts the count again
+	g = chess.NewGame(mustParseFEN(t, "4k3/8/8/8/8/8/4P3/R3K3 w - - 99 80"))
+	mustPlayGame(t, g, "e2e4")
+	if g.Position.HalfmoveClock != 0 {
+		t.Errorf("halfmove clock %d after a pawn move", g.Position.HalfmoveClock)
+	}
+	if outcome := g.Outcome(); outcome.IsOver() {
+		t.Errorf("%s after a pawn move", outcome)
+	}
+
+	// mate on the hundredth halfmove is still mate
+	g = chess.NewGame(mustParseFEN(t, "6k1/5ppp/8/8/8/8/8/R5K1 w - - 99 80"))
+	mustPlayGame(t, g, "a1a8")
+	if outcome := g.Outcome(); outcome.Termination != chess.TerminationCheckmate {
+		t.Errorf("%s, want checkmate", outcome)
+	}
+}
+
+func TestInsufficientMaterial(t *testing.T) {
+	for _, tc := range []struct {
+		fen          string
+		insufficient bool
+	}{
+		{"4k3/8/8/8/8/8/8/4K3 w - - 0 1", true},
+		{"4k3/8/8/8/8/8/8/2B1K3 w - - 0 1", true},
+		{"4k3/8/8/8/8/8/8/1N2K3 w - - 0 1", true},
+		{"4kb2/8/8/8/8/8/8/2B1K3 w - - 0 1", true},
+		{"2b1k3/8/8/8/8/8/8/2B1K3 w - - 0 1", false},
+		{"4k3/8/8/8/8/8/8/1NB1K3 w - - 0 1", false},
+		{"4k3/8/8/8/8/8/8/1N1NK3 w - - 0 1", false},
+		{"4k3/8/8/8/8/8/4P3/4K3 w - - 0 1", false},
+		{"4k3/8/8/8/8/8/8/3RK3 w - - 0 1", false},
+		{"4k3/8/8/8/8/8/8/3QK3 w - - 0 1", false},
+	} {
+		p := mustParseFEN(t, tc.fen)
+		if got := p.InsufficientMaterial(); got != tc.insufficient {
+			t.Errorf("%s: insufficient material is %v, want %v", tc.fen, got, tc.insufficient)
+		}
+		outcome := chess.NewGame(p).Outcome()
+		if drawn := outcome.Termination == chess.TerminationInsufficientMaterial; drawn != tc.insufficient {
+			t.Errorf("%s: %s", tc.fen, outcome)
+		}
+	}
+}
+
+func TestBoardOutcome(t *testing.T) {
+	for _, tc := range []struct {
+		fen     string
+		outcome chess.Outcome
+	}{
+		{notation.StartingFEN, chess.Outcome{Result: chess.ResultOngoing, Termination: chess.TerminationNone}},
+		{"R5k1/5ppp/8/8/8/8/8/6K1 b - - 0 1", chess.Outcome{Result: chess.ResultWhiteWins, Termination: chess.TerminationCheckmate}},
+		{"7k/5Q2/6K1/8/8/8/8/8 b - - 0 1", chess.Outcome{Result: chess.ResultDraw, Termination: chess.TerminationStalemate}},
+	} {
+		p := mustParseFEN(t, tc.fen)
+		if outcome := p.Outcome(); outcome != tc.outcome {
+			t.Errorf("%s: %s, want %s", tc.fen, outcome, tc.outcome)
+		}
+	}
+}
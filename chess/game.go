@@ -0,0 +1,242 @@
+package chess
+
+import "fmt"
+
+// a position plus everything that's happened to get there, which some of the rules need
+type Game struct {
+	// where the game started, left alone as the game goes on
+	Start    *Position
+	Position *Position
+	Moves    []Move
+	// the game as it'd be written down, see RecordEntry
+	Record []RecordEntry
+	// optional, if it's set it's wound back and forward along with the moves by Undo and Redo
+	Clock *Clock
+	// hash of every position reached, starting with the initial one
+	hashes []uint64
+
+	undos []gameStep
+	redos []gameStep
+}
+
+// one move, pass or piece taken off, which is what Undo and Redo go by. comments come along with whatever
+// they were written after
+type gameStep struct {
+	kind RecordKind
+	// for moves and passes
+	undo Undo
+	// on the undo stack, the clock from before the step and where the step starts in Record
+	// on the redo stack, the clock from when it was taken back and the entries it took out of Record
+	clock   ClockState
+	record  int
+	entries []RecordEntry
+}
+
+type RecordKind int
+
+const (
+	RecordMove    RecordKind = iota
+	RecordPass    RecordKind = iota
+	RecordRemoval RecordKind = iota
+	RecordComment RecordKind = iota
+)
+
+// one thing that happened, in order. Move is set for moves, Square and Piece for pieces taken off and
+// Comment for comments. replaying these from Start gets back to Position
+type RecordEntry struct {
+	Kind    RecordKind
+	Move    Move
+	Square  Square
+	Piece   Piece
+	Comment string
+}
+
+func NewGame(position *Position) *Game {
+	return &Game{
+		Start:    position.Copy(),
+		Position: position,
+		Moves:    make([]Move, 0),
+		Record:   make([]RecordEntry, 0),
+		hashes:   []uint64{position.Hash()},
+	}
+}
+
+func (g *Game) Play(m Move) error {
+	if err := g.play(m); err != nil {
+		return err
+	}
+	g.redos = g.redos[:0]
+	return nil
+}
+
+func (g *Game) play(m Move) error {
+	piece := g.Position.PieceAt(m.From)
+	if piece.IsEmpty() {
+		return fmt.Errorf("no piece on %s to move", m.From)
+	}
+	g.pushStep(RecordMove, g.Position.UndoFor(m))
+	// in real-time games a side can move twice in a row, on paper the other side passed in between
+	// the position passes too so its move counters agree with the record, the undo from before covers both
+	if piece.Color != g.Position.SideToMove {
+		g.Position.Pass()
+		g.Record = append(g.Record, RecordEntry{Kind: RecordPass})
+	}
+	if err := g.Position.MakeMove(m); err != nil {
+		return err
+	}
+	g.Moves = append(g.Moves, m)
+	g.Record = append(g.Record, RecordEntry{Kind: RecordMove, Move: m})
+	g.hashes = append(g.hashes, g.Position.Hash())
+	return nil
+}
+
+// skipped turns don't show up in Moves, but the position they leave still counts for repetition
+func (g *Game) Pass() {
+	g.pass()
+	g.redos = g.redos[:0]
+}
+
+func (g *Game) pass() {
+	g.pushStep(RecordPass, g.Position.UndoForPass())
+	g.Position.Pass()
+	g.Record = append(g.Record, RecordEntry{Kind: RecordPass})
+	g.hashes = append(g.hashes, g.Position.Hash())
+}
+
+// for pieces taken off outside of normal play (variants), the current position's hash changes in place
+func (g *Game) RemovePiece(square Square) {
+	g.removePiece(square)
+	g.redos = g.redos[:0]
+}
+
+func (g *Game) removePiece(square Square) {
+	g.pushStep(RecordRemoval, Undo{})
+	piece := g.Position.PieceAt(square)
+	g.Position.SetPiece(square, NoPiece)
+	g.Record = append(g.Record, RecordEntry{Kind: RecordRemoval, Square: square, Piece: piece})
+	g.hashes[len(g.hashes)-1] = g.Position.Hash()
+}
+
+// notes something in the record at this point in the game
+func (g *Game) Comment(comment string) {
+	g.Record = append(g.Record, RecordEntry{Kind: RecordComment, Comment: comment})
+}
+
+func (g *Game) pushStep(kind RecordKind, undo Undo) {
+	step := gameStep{kind: kind, undo: undo, record: len(g.Record)}
+	if g.Clock != nil {
+		step.clock = g.Clock.State()
+	}
+	g.undos = append(g.undos, step)
+}
+
+// the move, pass or piece taken off that Undo would take back
+func (g *Game) UndoEntry() (RecordEntry, bool) {
+	if len(g.undos) == 0 {
+		return RecordEntry{}, false
+	}
+	step := g.undos[len(g.undos)-1]
+	for _, entry := range g.Record[step.record:] {
+		if entry.Kind == step.kind {
+			return entry, true
+		}
+	}
+	return RecordEntry{}, false
+}
+
+func (g *Game) CanRedo() bool {
+	return len(g.redos) > 0
+}
+
+// takes back the last move, pass or piece taken off, along with any comments after it and the clock time
+// since. false if there's nothing left to take back
+func (g *Game) Undo() bool {
+	if len(g.undos) == 0 {
+		return false
+	}
+	step := g.undos[len(g.undos)-1]
+	g.undos = g.undos[:len(g.undos)-1]
+
+	redo := gameStep{kind: step.kind, entries: append([]RecordEntry(nil), g.Record[step.record:]...)}
+	if g.Clock != nil {
+		redo.clock = g.Clock.State()
+		g.Clock.Restore(step.clock)
+	}
+	g.redos = append(g.redos, redo)
+
+	switch step.kind {
+	case RecordMove:
+		g.Position.Unmake(step.undo)
+		g.Moves = g.Moves[:len(g.Moves)-1]
+		g.hashes = g.hashes[:len(g.hashes)-1]
+	case RecordPass:
+		g.Position.Unmake(step.undo)
+		g.hashes = g.hashes[:len(g.hashes)-1]
+	case RecordRemoval:
+		removal := g.Record[step.record]
+		g.Position.SetPiece(removal.Square, removal.Piece)
+		g.hashes[len(g.hashes)-1] = g.Position.Hash()
+	}
+	g.Record = g.Record[:step.record]
+	return true
+}
+
+// puts back whatever Undo last took back, clock included. anything played since the undo clears what there
+// was to redo, so this is false then
+func (g *Game) Redo() (bool, error) {
+	if len(g.redos) == 0 {
+		return false, nil
+	}
+	step := g.redos[len(g.redos)-1]
+	g.redos = g.redos[:len(g.redos)-1]
+
+	for _, entry := range step.entries {
+		switch {
+		case entry.Kind == RecordComment:
+			g.Comment(entry.Comment)
+		case entry.Kind != step.kind:
+			// the pass written before a real-time move out of turn, play puts it back
+		case entry.Kind == RecordMove:
+			if err := g.play(entry.Move); err != nil {
+				return false, err
+			}
+		case entry.Kind == RecordPass:
+			g.pass()
+		case entry.Kind == RecordRemoval:
+			g.removePiece(entry.Square)
+		}
+	}
+	if g.Clock != nil {
+		g.Clock.Restore(step.clock)
+	}
+	return true, nil
+}
+
+// how many times the current position has come up, including now
+func (g *Game) Repetitions() int {
+	current := g.hashes[len(g.hashes)-1]
+	count := 0
+	for _, hash := range g.hashes {
+		if hash == current {
+			count++
+		}
+	}
+	return count
+}
+
+// board outcome plus the draws that need history or are adjudicated automatically
+func (g *Game) Outcome() Outcome {
+	if outcome := g.Position.Outcome(); outcome.IsOver() {
+		return outcome
+	}
+	if g.Position.HalfmoveClock >= 100 {
+		return Outcome{ResultDraw, TerminationFiftyMoves}
+	}
+	if g.Repetitions() >= 3 {
+		return Outcome{ResultDraw, TerminationRepetition}
+	}
+	if g.Position.InsufficientMaterial() {
+		return Outcome{ResultDraw, TerminationInsufficientMaterial}
+	}
+	return Outcome{ResultOngoing, TerminationNone}
+}
@@ -0,0 +1,22 @@
+package chess
+
+type Move struct {
+	From      Square
+	To        Square
+	Promotion PieceType
+}
+
+func (m Move) String() string {
+	s := m.From.String() + m.To.String()
+	switch m.Promotion {
+	case Knight:
+		s += "n"
+	case Bishop:
+		s += "b"
+	case Rook:
+		s += "r"
+	case Queen:
+		s += "q"
+	}
+	return s
+}
@@ -0,0 +1,259 @@
+package chess
+
+// every move for the side to move
+func (p *Position) LegalMoves() []Move {
+	moves := make([]Move, 0)
+	for sq := Square(0); sq < 64; sq++ {
+		if piece := p.Board[sq]; !piece.IsEmpty() && piece.Color == p.SideToMove {
+			moves = append(moves, p.MovesFrom(sq)...)
+		}
+	}
+	return moves
+}
+
+// moves for whatever piece is on the square, regardless of whose turn it is
+// anything that would leave the mover's own king attacked is dropped
+func (p *Position) MovesFrom(from Square) []Move {
+	pseudo := p.PseudoLegalMovesFrom(from)
+	moves := make([]Move, 0, len(pseudo))
+	color := p.PieceAt(from).Color
+	for _, move := range pseudo {
+		after := p.Copy()
+		if err := after.MakeMove(move); err != nil {
+			continue
+		}
+		if !after.InCheck(color) {
+			moves = append(moves, move)
+		}
+	}
+	return moves
+}
+
+// moves following how the pieces move, without caring about the king
+func (p *Position) PseudoLegalMovesFrom(from Square) []Move {
+	piece := p.PieceAt(from)
+	if piece.IsEmpty() {
+		return []Move{}
+	}
+
+	targets := make([]Square, 0)
+	add := func(files, ranks int) {
+		if to := from.Offset(files, ranks); to != NoSquare {
+			targets = append(targets, to)
+		}
+	}
+
+	// walks out from the piece until it runs off the board or into something, which it can take if it's an enemy
+	addRay := func(files, ranks int) {
+		for to := from.Offset(files, ranks); to != NoSquare; to = to.Offset(files, ranks) {
+			targets = append(targets, to)
+			if !p.Board[to].IsEmpty() {
+				break
+			}
+		}
+	}
+
+	switch piece.Type {
+	case Pawn:
+		return p.pawnMoves(from, piece.Color)
+	case Rook:
+		for _, d := range rookDirections {
+			addRay(d[0], d[1])
+		}
+	case Bishop:
+		for _, d := range bishopDirections {
+			addRay(d[0], d[1])
+		}
+	case Knight:
+		for _, d := range knightOffsets {
+			add(d[0], d[1])
+		}
+	case King:
+		for _, d := range kingOffsets {
+			add(d[0], d[1])
+		}
+		targets = append(targets, p.castlingTargets(from, piece.Color)...)
+	case Queen:
+		for _, d := range rookDirections {
+			addRay(d[0], d[1])
+		}
+		for _, d := range bishopDirections {
+			addRay(d[0], d[1])
+		}
+	}
+
+	moves := make([]Move, 0, len(targets))
+	for _, to := range targets {
+		// can't take your own pieces, and kings are never taken (real-time games could otherwise get there)
+		if occupant := p.Board[to]; !occupant.IsEmpty() && (occupant.Color == piece.Color || occupant.Type == King) {
+			continue
+		}
+		moves = append(moves, Move{From: from, To: to})
+	}
+	return moves
+}
+func (p *Position) pawnMoves(from Square, color Color) []Move {
+	dir, startRank, lastRank := 1, 1, 7
+	if color == Black {
+		dir, startRank, lastRank = -1, 6, 0
+	}
+
+	targets := make([]Square, 0)
+	// pushes, only ever onto empty squares
+	if one := from.Offset(0, dir); one != NoSquare && p.Board[one].IsEmpty() {
+		targets = append(targets, one)
+		if two := from.Offset(0, 2*dir); from.Rank() == startRank && p.Board[two].IsEmpty() {
+			targets = append(targets, two)
+		}
+	}
+	// diagonals, only when taking something. en passant only counts for the side that's actually on move
+	for _, files := range []int{-1, 1} {
+		to := from.Offset(files, dir)
+		if to == NoSquare {
+			continue
+		}
+		occupant := p.Board[to]
+		if (!occupant.IsEmpty() && occupant.Color != color && occupant.Type != King) ||
+			(to == p.EnPassant && color == p.SideToMove) {
+			targets = append(targets, to)
+		}
+	}
+
+	moves := make([]Move, 0, len(targets))
+	for _, to := range targets {
+		if to.Rank() == lastRank {
+			for _, promotion := range PromotionPieces {
+				moves = append(moves, Move{From: from, To: to, Promotion: promotion})
+			}
+		} else {
+			moves = append(moves, Move{From: from, To: to})
+		}
+	}
+	return moves
+}
+
+// king destinations for any castling that's currently allowed
+// the king can't castle out of, through or into check, into is handled by the usual legality filter
+func (p *Position) castlingTargets(from Square, color Color) []Square {
+	targets := make([]Square, 0)
+	rank := 0
+	kingside, queenside := WhiteKingside, WhiteQueenside
+	if color == Black {
+		rank = 7
+		kingside, queenside = BlackKingside, BlackQueenside
+	}
+	if from != NewSquare(4, rank) || p.InCheck(color) {
+		return targets
+	}
+	empty := func(files ...int) bool {
+		for _, file := range files {
+			if !p.Board[NewSquare(file, rank)].IsEmpty() {
+				return false
+			}
+		}
+		return true
+	}
+	if p.Castling&kingside != 0 && empty(5, 6) && !p.IsAttacked(NewSquare(5, rank), color.Other()) {
+		targets = append(targets, NewSquare(6, rank))
+	}
+	if p.Castling&queenside != 0 && empty(1, 2, 3) && !p.IsAttacked(NewSquare(3, rank), color.Other()) {
+		targets = append(targets, NewSquare(2, rank))
+	}
+	return targets
+}
+
+// a move is a promotion if there are legal promotions from/to the same squares, regardless of the chosen piece
+func (p *Position) IsPromotion(from, to Square) bool {
+	for _, move := range p.MovesFrom(from) {
+		if move.To == to && move.Promotion != NoPieceType {
+			return true
+		}
+	}
+	return false
+}
+
+// whether any piece of the given colour could take on the square, pawns only count their diagonals
+func (p *Position) IsAttacked(square Square, by Color) bool {
+	// look outwards from the square for each kind of attacker
+	pawnDir := -1
+	if by == Black {
+		pawnDir = 1
+	}
+	for _, files := range []int{-1, 1} {
+		if piece := p.PieceAt(square.Offset(files, pawnDir)); piece == (Piece{by, Pawn}) {
+			return true
+		}
+	}
+	for _, d := range knightOffsets {
+		if piece := p.PieceAt(square.Offset(d[0], d[1])); piece == (Piece{by, Knight}) {
+			return true
+		}
+	}
+	for _, d := range kingOffsets {
+		if piece := p.PieceAt(square.Offset(d[0], d[1])); piece == (Piece{by, King}) {
+			return true
+		}
+	}
+	slider := func(directions [4][2]int, pieceType PieceType) bool {
+		for _, d := range directions {
+			for sq := square.Offset(d[0], d[1]); sq != NoSquare; sq = sq.Offset(d[0], d[1]) {
+				piece := p.Board[sq]
+				if piece.IsEmpty() {
+					continue
+				}
+				if piece.Color == by && (piece.Type == pieceType || piece.Type == Queen) {
+					return true
+				}
+				break
+			}
+		}
+		return false
+	}
+	return slider(rookDirections, Rook) || slider(bishopDirections, Bishop)
+}
+
+func (p *Position) KingSquare(color Color) Square {
+	for sq := Square(0); sq < 64; sq++ {
+		if p.Board[sq] == (Piece{color, King}) {
+			return sq
+		}
+	}
+	return NoSquare
+}
+
+// a side without a king (e.g. a puzzle setup) is never in check
+func (p *Position) InCheck(color Color) bool {
+	king := p.KingSquare(color)
+	if king == NoSquare {
+		return false
+	}
+	return p.IsAttacked(king, color.Other())
+}
+
+func (p *Position) IsLegal(m Move) bool {
+	for _, legal := range p.MovesFrom(m.From) {
+		if legal == m {
+			return true
+		}
+	}
+	return false
+}
+
+var rookDirections = [4][2]int{
+	{1, 0}, {0, 1}, {-1, 0}, {0, -1},
+}
+
+var bishopDirections = [4][2]int{
+	{1, 1}, {-1, 1}, {-1, -1}, {1, -1},
+}
+
+// counter-clockwise from far right
+var knightOffsets = [8][2]int{
+	{2, 1}, {1, 2}, {-1, 2}, {-2, 1},
+	{-2, -1}, {-1, -2}, {1, -2}, {2, -1},
+}
+
+var kingOffsets = [8][2]int{
+	{1, 0}, {1, 1}, {0, 1}, {-1, 1},
+	{-1, 0}, {-1, -1}, {0, -1}, {1, -1},
+}
@@ -0,0 +1,150 @@
+package chess
+
+type Result int
+
+const (
+	ResultOngoing   Result = iota
+	ResultWhiteWins Result = iota
+	ResultBlackWins Result = iota
+	ResultDraw      Result = iota
+)
+
+// in pgn notation
+func (r Result) String() string {
+	switch r {
+	case ResultWhiteWins:
+		return "1-0"
+	case ResultBlackWins:
+		return "0-1"
+	case ResultDraw:
+		return "1/2-1/2"
+	}
+	return "*"
+}
+
+func WinFor(color Color) Result {
+	if color == White {
+		return ResultWhiteWins
+	}
+	return ResultBlackWins
+}
+
+type Termination int
+
+const (
+	TerminationNone      Termination = iota
+	TerminationCheckmate Termination = iota
+	TerminationStalemate Termination = iota
+
+	TerminationFiftyMoves           Termination = iota
+	TerminationRepetition           Termination = iota
+	TerminationInsufficientMaterial Termination = iota
+
+	TerminationTimeout Termination = iota
+
+	// variant rules, for a side that's been knocked out some other way than on the board
+	TerminationLives Termination = iota
+)
+
+func (t Termination) String() string {
+	switch t {
+	case TerminationCheckmate:
+		return "checkmate"
+	case TerminationStalemate:
+		return "stalemate"
+	case TerminationFiftyMoves:
+		return "fifty-move rule"
+	case TerminationRepetition:
+		return "threefold repetition"
+	case TerminationInsufficientMaterial:
+		return "insufficient material"
+	case TerminationTimeout:
+		return "timeout"
+	case TerminationLives:
+		return "running out of lives"
+	}
+	return "none"
+}
+
+type Outcome struct {
+	Result      Result
+	Termination Termination
+}
+
+func (o Outcome) IsOver() bool {
+	return o.Result != ResultOngoing
+}
+
+func (o Outcome) String() string {
+	if !o.IsOver() {
+		return "game in progress"
+	}
+	switch o.Result {
+	case ResultWhiteWins:
+		return "white wins by " + o.Termination.String()
+	case ResultBlackWins:
+		return "black wins by " + o.Termination.String()
+	}
+	return "draw by " + o.Termination.String()
+}
+
+// outcome decided by the board alone, i.e. whether the side to move has anything left to play
+func (p *Position) Outcome() Outcome {
+	if len(p.LegalMoves()) > 0 {
+		return Outcome{ResultOngoing, TerminationNone}
+	}
+	if p.InCheck(p.SideToMove) {
+		return Outcome{WinFor(p.SideToMove.Other()), TerminationCheckmate}
+	}
+	return Outcome{ResultDraw, TerminationStalemate}
+}
+
+// neither side can possibly mate: bare kings, a single minor piece, or only bishops that all share a square colour
+func (p *Position) InsufficientMaterial() bool {
+	minors := 0
+	bishopSquareColors := [2]int{}
+	knights := 0
+	for sq := Square(0); sq < 64; sq++ {
+		switch p.Board[sq].Type {
+		case Pawn, Rook, Queen:
+			return false
+		case Knight:
+			minors++
+			knights++
+		case Bishop:
+			minors++
+			bishopSquareColors[(sq.File()+sq.Rank())%2]++
+		}
+	}
+	if minors <= 1 {
+		return true
+	}
+	return knights == 0 && (bishopSquareColors[0] == 0 || bishopSquareColors[1] == 0)
+}
+
+// running out of time loses, unless the opponent couldn't have mated anyway
+func (p *Position) TimeoutOutcome(flagged Color) Outcome {
+	if !p.CanMate(flagged.Other()) {
+		return Outcome{ResultDraw, TerminationTimeout}
+	}
+	return Outcome{WinFor(flagged.Other()), TerminationTimeout}
+}
+
+// whether a side has enough material to mate by any series of legal moves
+// (the lone minor piece cases are the only ones ruled out, which is what the clock rules use)
+func (p *Position) CanMate(color Color) bool {
+	minors := 0
+	for sq := Square(0); sq < 64; sq++ {
+		piece := p.Board[sq]
+		if piece.Color != color || piece.IsEmpty() {
+			continue
+		}
+		switch piece.Type {
+		case Pawn, Rook, Queen:
+			return true
+		case Knight, Bishop:
+			minors++
+		}
+	}
+	return minors > 1
+}
@@ -0,0 +1,148 @@
+package chess_test
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+	"github.com/val-is/bullet-hell-chess/notation"
+)
+
+func mustParseSquare(t *testing.T, name string) chess.Square {
+	t.Helper()
+	square, err := notation.ParseSquare(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return square
+}
+
+// the legal moves from a square in uci, sorted and space separated so they're easy to compare
+func movesFrom(t *testing.T, p *chess.Position, from string) string {
+	t.Helper()
+	moves := p.MovesFrom(mustParseSquare(t, from))
+	names := make([]string, len(moves))
+	for i, move := range moves {
+		names[i] = move.String()
+	}
+	sort.Strings(names)
+	return strings.Join(names, " ")
+}
+
+func mustPlay(t *testing.T, p *chess.Position, uci string) {
+	t.Helper()
+	move, err := notation.ParseUCIMove(p, uci)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.MakeMove(move); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPawnMoves(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		fen   string
+		from  string
+		moves string
+	}{
+		{"double step from the start", notation.StartingFEN, "e2", "e2e3 e2e4"},
+		{"black double step from the start", "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1", "d7", "d7d5 d7d6"},
+		{"no double step after the first", "4k3/8/8/8/8/4P3/8/4K3 w - - 0 1", "e3", "e3e4"},
+		{"double step blocked on the far square", "4k3/8/8/8/4n3/8/4P3/4K3 w - - 0 1", "e2", "e2e3"},
+		{"double step blocked on the near square", "4k3/8/8/8/8/4n3/4P3/4K3 w - - 0 1", "e2", ""},
+		{"diagonals only onto pieces", "4k3/8/8/8/8/3p1P2/4P3/4K3 w - - 0 1", "e2", "e2d3 e2e3 e2e4"},
+		{"never onto its own side", "4k3/8/8/8/8/3P1P2/4P3/4K3 w - - 0 1", "e2", "e2e3 e2e4"},
+		{"pinned to the king", "4k3/8/8/1b6/8/8/4P3/5K2 w - - 0 1", "e2", ""},
+		{"can take the pinning piece", "4k3/8/8/8/8/5b2/4P3/3K4 w - - 0 1", "e2", "e2f3"},
+	} {
+		p := mustParseFEN(t, tc.fen)
+		if moves := movesFrom(t, p, tc.from); moves != tc.moves {
+			t.Errorf("%s: %s has %q, want %q", tc.name, tc.from, moves, tc.moves)
+		}
+	}
+}
+
+func TestEnPassant(t *testing.T) {
+	p := mustParseFEN(t, "4k3/3p4/8/4P3/8/8/8/4K3 b - - 0 1")
+	mustPlay(t, p, "d7d5")
+	if want := mustParseSquare(t, "d6"); p.EnPassant != want {
+		t.Fatalf("en passant square %v after d7d5, want d6", p.EnPassant)
+	}
+	if moves := movesFrom(t, p, "e5"); moves != "e5d6 e5e6" {
+		t.Errorf("e5 has %q right after d7d5, want en passant", moves)
+	}
+
+	take := p.Copy()
+	mustPlay(t, take, "e5d6")
+	if piece := take.PieceAt(mustParseSquare(t, "d5")); !piece.IsEmpty() {
+		t.Errorf("d5 still has %v after en passant", piece)
+	}
+	if piece := take.PieceAt(mustParseSquare(t, "d6")); piece != (chess.Piece{Color: chess.White, Type: chess.Pawn}) {
+		t.Errorf("d6 has %v after en passant", piece)
+	}
+	if take.HalfmoveClock != 0 {
+		t.Errorf("halfmove clock %d after en passant", take.HalfmoveClock)
+	}
+
+	// only straight after the double step
+	mustPlay(t, p, "e1f1")
+	mustPlay(t, p, "e8f8")
+	if p.EnPassant != chess.NoSquare {
+		t.Errorf("en passant square %v a move later", p.EnPassant)
+	}
+	if moves := movesFrom(t, p, "e5"); moves != "e5e6" {
+		t.Errorf("e5 has %q a move later, want no en passant", moves)
+	}
+
+	// a single step doesn't give one
+	p = mustParseFEN(t, "4k3/8/3p4/4P3/8/8/8/4K3 b - - 0 1")
+	mustPlay(t, p, "d6d5")
+	if p.EnPassant != chess.NoSquare {
+		t.Errorf("en passant square %v after a single step", p.EnPassant)
+	}
+
+	// taking would leave both pawns off the fifth rank and the king open to the rook
+	p = mustParseFEN(t, "8/8/8/KPp4r/8/8/8/7k w - c6 0 1")
+	if moves := movesFrom(t, p, "b5"); moves != "b5b6" {
+		t.Errorf("b5 has %q, want no en passant with the king behind it", moves)
+	}
+}
+
+func TestPromotion(t *testing.T) {
+	p := mustParseFEN(t, "1n2k3/P7/8/8/8/8/8/4K3 w - - 0 1")
+	want := "a7a8b a7a8n a7a8q a7a8r a7b8b a7b8n a7b8q a7b8r"
+	if moves := movesFrom(t, p, "a7"); moves != want {
+		t.Errorf("a7 has %q, want %q", moves, want)
+	}
+	if !p.IsPromotion(mustParseSquare(t, "a7"), mustParseSquare(t, "a8")) {
+		t.Error("a7a8 isn't a promotion")
+	}
+	if p.IsLegal(chess.Move{From: mustParseSquare(t, "a7"), To: mustParseSquare(t, "a8")}) {
+		t.Error("a7a8 is legal without a piece to promote to")
+	}
+
+	for _, tc := range []struct {
+		uci   string
+		piece chess.PieceType
+	}{{"a7a8q", chess.Queen}, {"a7a8n", chess.Knight}, {"a7b8r", chess.Rook}, {"a7b8b", chess.Bishop}} {
+		after := p.Copy()
+		mustPlay(t, after, tc.uci)
+		to := mustParseSquare(t, tc.uci[2:4])
+		if piece := after.PieceAt(to); piece != (chess.Piece{Color: chess.White, Type: tc.piece}) {
+			t.Errorf("%s left %v on %s", tc.uci, piece, tc.uci[2:4])
+		}
+		if piece := after.PieceAt(mustParseSquare(t, "a7")); !piece.IsEmpty() {
+			t.Errorf("%s left %v on a7", tc.uci, piece)
+		}
+	}
+
+	// and the same for black on the first rank
+	p = mustParseFEN(t, "4k3/8/8/8/8/8/6p1/4K2R b - - 0 1")
+	want = "g2g1b g2g1n g2g1q g2g1r g2h1b g2h1n g2h1q g2h1r"
+	if moves := movesFrom(t, p, "g2"); moves != want {
+		t.Errorf("g2 has %q, want %q", moves, want)
+	}
+}
@@ -0,0 +1,75 @@
+package chess_test
+
+import (
+	"testing"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+	"github.com/val-is/bullet-hell-chess/notation"
+)
+
+// number of move sequences depth plies long, the usual way to check a move generator against known counts
+func perft(t *testing.T, p *chess.Position, depth int) int {
+	moves := p.LegalMoves()
+	if depth == 1 {
+		return len(moves)
+	}
+	nodes := 0
+	for _, move := range moves {
+		after := p.Copy()
+		if err := after.MakeMove(move); err != nil {
+			t.Fatal(err)
+		}
+		nodes += perft(t, after, depth-1)
+	}
+	return nodes
+}
+
+func mustParseFEN(t *testing.T, fen string) *chess.Position {
+	t.Helper()
+	p, err := notation.ParseFEN(fen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+type perftCase struct {
+	name  string
+	fen   string
+	depth int
+	nodes int
+}
+
+func runPerft(t *testing.T, cases []perftCase) {
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if nodes := perft(t, mustParseFEN(t, tc.fen), tc.depth); nodes != tc.nodes {
+				t.Errorf("perft(%d) = %d, want %d", tc.depth, nodes, tc.nodes)
+			}
+		})
+	}
+}
+
+func TestPerft(t *testing.T) {
+	runPerft(t, []perftCase{
+		// a knight and kings on an open board, nothing in anyone's way
+		{"knight-1", "k7/8/8/8/3N4/8/8/7K w - - 0 1", 1, 11},
+		// sliders stop at the first piece in the way, taking it if it's the other side's
+		{"rooks-1", "4k3/8/8/8/8/8/8/R3K2R w - - 0 1", 1, 24},
+		{"blocked-1", "4k3/8/8/8/3n4/8/8/B2R3K w - - 0 1", 1, 14},
+		// counts from the chess programming wiki's perft results page
+		{"startpos-1", notation.StartingFEN, 1, 20},
+		{"startpos-2", notation.StartingFEN, 2, 400},
+		{"startpos-3", notation.StartingFEN, 3, 8902},
+		{"startpos-4", notation.StartingFEN, 4, 197281},
+		{"kiwipete-1", "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", 1, 48},
+		{"kiwipete-2", "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", 2, 2039},
+		{"kiwipete-3", "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", 3, 97862},
+		{"position3-3", "8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1", 3, 2812},
+		{"position3-5", "8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1", 5, 674624},
+		{"position4-2", "r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1", 2, 264},
+		{"position4-4", "r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1", 4, 422333},
+		{"position5-2", "rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8", 2, 1486},
+		{"position5-3", "rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8", 3, 62379},
+	})
+}
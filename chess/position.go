@@ -0,0 +1,281 @@
+package chess
+
+import "fmt"
+
+type CastlingRights uint8
+
+const (
+	WhiteKingside  CastlingRights = 1 << iota
+	WhiteQueenside CastlingRights = 1 << iota
+	BlackKingside  CastlingRights = 1 << iota
+	BlackQueenside CastlingRights = 1 << iota
+
+	NoCastling  CastlingRights = 0
+	AllCastling                = WhiteKingside | WhiteQueenside | BlackKingside | BlackQueenside
+)
+
+// full state of a game at a single point in time, independent of anything drawn on screen
+type Position struct {
+	Board          [64]Piece
+	SideToMove     Color
+	Castling       CastlingRights
+	EnPassant      Square
+	HalfmoveClock  int
+	FullmoveNumber int
+}
+
+func NewEmptyPosition() *Position {
+	return &Position{
+		SideToMove:     White,
+		Castling:       NoCastling,
+		EnPassant:      NoSquare,
+		HalfmoveClock:  0,
+		FullmoveNumber: 1,
+	}
+}
+
+func NewStartingPosition() *Position {
+	p := NewEmptyPosition()
+	backRank := []PieceType{Rook, Knight, Bishop, Queen, King, Bishop, Knight, Rook}
+	for file, pieceType := range backRank {
+		p.Board[NewSquare(file, 0)] = Piece{White, pieceType}
+		p.Board[NewSquare(file, 1)] = Piece{White, Pawn}
+		p.Board[NewSquare(file, 6)] = Piece{Black, Pawn}
+		p.Board[NewSquare(file, 7)] = Piece{Black, pieceType}
+	}
+	p.Castling = AllCastling
+	return p
+}
+
+func (p *Position) Copy() *Position {
+	c := *p
+	return &c
+}
+
+func (p *Position) PieceAt(square Square) Piece {
+	if !square.Valid() {
+		return NoPiece
+	}
+	return p.Board[square]
+}
+
+func (p *Position) SetPiece(square Square, piece Piece) {
+	if square.Valid() {
+		p.Board[square] = piece
+	}
+}
+
+// square of the piece that would be taken by the move, which is only different from the destination for en passant
+func (p *Position) CaptureSquare(m Move) Square {
+	piece := p.PieceAt(m.From)
+	if piece.Type == Pawn && m.To == p.EnPassant && m.From.File() != m.To.File() && p.PieceAt(m.To).IsEmpty() {
+		return NewSquare(m.To.File(), m.From.Rank())
+	}
+	if p.PieceAt(m.To).IsEmpty() {
+		return NoSquare
+	}
+	return m.To
+}
+
+// piece that would be taken by the move, NoPiece if it's a quiet move
+func (p *Position) CapturedBy(m Move) Piece {
+	return p.PieceAt(p.CaptureSquare(m))
+}
+
+// the rook's half of a castling move, if the move is one
+func (p *Position) CastlingRookMove(m Move) (Move, bool) {
+	if p.PieceAt(m.From).Type != King || m.From.Rank() != m.To.Rank() {
+		return Move{}, false
+	}
+	switch m.To.File() - m.From.File() {
+	case 2:
+		return Move{From: NewSquare(7, m.From.Rank()), To: NewSquare(5, m.From.Rank())}, true
+	case -2:
+		return Move{From: NewSquare(0, m.From.Rank()), To: NewSquare(3, m.From.Rank())}, true
+	}
+	return Move{}, false
+}
+
+// rights lost when anything moves from or to these squares
+var castlingSquareRights = map[Square]CastlingRights{
+	NewSquare(4, 0): WhiteKingside | WhiteQueenside,
+	NewSquare(7, 0): WhiteKingside,
+	NewSquare(0, 0): WhiteQueenside,
+	NewSquare(4, 7): BlackKingside | BlackQueenside,
+	NewSquare(7, 7): BlackKingside,
+	NewSquare(0, 7): BlackQueenside,
+}
+
+// applies a move without checking it, use IsLegal first for anything coming from a player
+func (p *Position) MakeMove(m Move) error {
+	piece := p.PieceAt(m.From)
+	if piece.IsEmpty() {
+		return fmt.Errorf("no piece on %s to move", m.From)
+	}
+	captureSquare := p.CaptureSquare(m)
+	captured := p.PieceAt(captureSquare)
+
+	if rookMove, ok := p.CastlingRookMove(m); ok {
+		p.Board[rookMove.To] = p.Board[rookMove.From]
+		p.Board[rookMove.From] = NoPiece
+	}
+	// moving the king or a rook gives up its castling, so does having the rook taken
+	p.Castling &^= castlingSquareRights[m.From] | castlingSquareRights[m.To]
+
+	if captureSquare != NoSquare {
+		p.Board[captureSquare] = NoPiece
+	}
+	p.Board[m.From] = NoPiece
+	if m.Promotion != NoPieceType {
+		p.Board[m.To] = Piece{piece.Color, m.Promotion}
+	} else {
+		p.Board[m.To] = piece
+	}
+
+	// only a double step leaves something to take en passant, and only for the very next move
+	p.EnPassant = NoSquare
+	if piece.Type == Pawn && (m.To.Rank()-m.From.Rank() == 2 || m.From.Rank()-m.To.Rank() == 2) {
+		p.EnPassant = NewSquare(m.From.File(), (m.From.Rank()+m.To.Rank())/2)
+	}
+
+	if piece.Type == Pawn || !captured.IsEmpty() {
+		p.HalfmoveClock = 0
+	} else {
+		p.HalfmoveClock++
+	}
+	if piece.Color == Black {
+		p.FullmoveNumber++
+	}
+	p.SideToMove = piece.Color.Other()
+	return nil
+}
+
+// everything a move or pass changes that can't be worked out from the position after it, so it can be taken back
+type Undo struct {
+	Move Move
+	Pass bool
+	// the piece that moved, a pawn for promotions
+	Moved         Piece
+	Captured      Piece
+	CaptureSquare Square
+
+	Castling       CastlingRights
+	EnPassant      Square
+	HalfmoveClock  int
+	FullmoveNumber int
+	SideToMove     Color
+}
+
+// has to be called before the move is made
+func (p *Position) UndoFor(m Move) Undo {
+	undo := p.UndoForPass()
+	undo.Pass = false
+	undo.Move = m
+	undo.Moved = p.PieceAt(m.From)
+	undo.CaptureSquare = p.CaptureSquare(m)
+	undo.Captured = p.PieceAt(undo.CaptureSquare)
+	return undo
+}
+
+func (p *Position) UndoForPass() Undo {
+	return Undo{
+		Pass:           true,
+		CaptureSquare:  NoSquare,
+		Castling:       p.Castling,
+		EnPassant:      p.EnPassant,
+		HalfmoveClock:  p.HalfmoveClock,
+		FullmoveNumber: p.FullmoveNumber,
+		SideToMove:     p.SideToMove,
+	}
+}
+
+// takes back the move or pass the undo was made for, which has to be the last thing that happened to the position
+func (p *Position) Unmake(u Undo) {
+	if !u.Pass {
+		p.Board[u.Move.To] = NoPiece
+		p.Board[u.Move.From] = u.Moved
+		// the king's back on its square, so this sees the castling again
+		if rookMove, ok := p.CastlingRookMove(u.Move); ok {
+			p.Board[rookMove.From] = p.Board[rookMove.To]
+			p.Board[rookMove.To] = NoPiece
+		}
+		if u.CaptureSquare != NoSquare {
+			p.Board[u.CaptureSquare] = u.Captured
+		}
+	}
+	p.Castling = u.Castling
+	p.EnPassant = u.EnPassant
+	p.HalfmoveClock = u.HalfmoveClock
+	p.FullmoveNumber = u.FullmoveNumber
+	p.SideToMove = u.SideToMove
+}
+
+// hands the move to the other side without moving anything, only used by variants
+func (p *Position) Pass() {
+	p.EnPassant = NoSquare
+	p.HalfmoveClock++
+	if p.SideToMove == Black {
+		p.FullmoveNumber++
+	}
+	p.SideToMove = p.SideToMove.Other()
+}
+
+// where the king and rook have to be for each castling right to make sense
+var castlingPieces = []struct {
+	right CastlingRights
+	name  string
+	color Color
+	king  Square
+	rook  Square
+}{
+	{WhiteKingside, "white kingside", White, NewSquare(4, 0), NewSquare(7, 0)},
+	{WhiteQueenside, "white queenside", White, NewSquare(4, 0), NewSquare(0, 0)},
+	{BlackKingside, "black kingside", Black, NewSquare(4, 7), NewSquare(7, 7)},
+	{BlackQueenside, "black queenside", Black, NewSquare(4, 7), NewSquare(0, 7)},
+}
+
+// checks for the things movegen and the rest of the rules take for granted, for positions that didn't come
+// from playing moves: one king each, no pawns on the back ranks, castling rights with the king and rook in
+// place, an en passant square a pawn could have just stepped over, and the side that just moved not in check
+func (p *Position) Validate() error {
+	for _, color := range []Color{White, Black} {
+		kings := 0
+		for sq := Square(0); sq < 64; sq++ {
+			if p.Board[sq] == (Piece{color, King}) {
+				kings++
+			}
+		}
+		if kings != 1 {
+			return fmt.Errorf("%s has %d kings", color, kings)
+		}
+	}
+	for file := 0; file < 8; file++ {
+		for _, rank := range []int{0, 7} {
+			if p.Board[NewSquare(file, rank)].Type == Pawn {
+				return fmt.Errorf("pawn on %s", NewSquare(file, rank))
+			}
+		}
+	}
+	for _, castling := range castlingPieces {
+		if p.Castling&castling.right == 0 {
+			continue
+		}
+		if p.Board[castling.king] != (Piece{castling.color, King}) || p.Board[castling.rook] != (Piece{castling.color, Rook}) {
+			return fmt.Errorf("%s castling without the king and rook in place", castling.name)
+		}
+	}
+	if p.EnPassant != NoSquare {
+		// the square behind a pawn that's just double stepped, so it's on the mover's 3rd rank
+		rank, pawnRank := 5, 4
+		if p.SideToMove == Black {
+			rank, pawnRank = 2, 3
+		}
+		if !p.EnPassant.Valid() || p.EnPassant.Rank() != rank || p.Board[NewSquare(p.EnPassant.File(), pawnRank)] != (Piece{p.SideToMove.Other(), Pawn}) {
+			return fmt.Errorf("no pawn could have just double stepped past %s", p.EnPassant)
+		}
+	}
+	if p.InCheck(p.SideToMove.Other()) {
+		return fmt.Errorf("%s is in check but it's %s to move", p.SideToMove.Other(), p.SideToMove)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package chess
+
+import "fmt"
+
+type Color int
+
+const (
+	White Color = iota
+	Black Color = iota
+)
+
+func (c Color) Other() Color {
+	if c == White {
+		return Black
+	}
+	return White
+}
+
+func (c Color) String() string {
+	if c == White {
+		return "white"
+	}
+	return "black"
+}
+
+type PieceType int
+
+const (
+	NoPieceType PieceType = iota
+	Pawn        PieceType = iota
+	Knight      PieceType = iota
+	Bishop      PieceType = iota
+	Rook        PieceType = iota
+	Queen       PieceType = iota
+	King        PieceType = iota
+)
+
+func (t PieceType) String() string {
+	switch t {
+	case Pawn:
+		return "pawn"
+	case Knight:
+		return "knight"
+	case Bishop:
+		return "bishop"
+	case Rook:
+		return "rook"
+	case Queen:
+		return "queen"
+	case King:
+		return "king"
+	}
+	return "none"
+}
+
+// in the order they're offered to the player
+var PromotionPieces = []PieceType{Queen, Rook, Bishop, Knight}
+
+// a piece sitting on a square, the zero value is an empty square
+type Piece struct {
+	Color Color
+	Type  PieceType
+}
+
+var NoPiece = Piece{}
+
+func (p Piece) IsEmpty() bool {
+	return p.Type == NoPieceType
+}
+
+// squares are indexed 0-63 starting from a1, going along the rank first (a1, b1, ... h8)
+type Square int
+
+const NoSquare Square = -1
+
+func NewSquare(file, rank int) Square {
+	if file < 0 || file > 7 || rank < 0 || rank > 7 {
+		return NoSquare
+	}
+	return Square(rank*8 + file)
+}
+
+// 0 indexed, a -> 0
+func (s Square) File() int {
+	return int(s) % 8
+}
+
+// 0 indexed, rank 1 -> 0
+func (s Square) Rank() int {
+	return int(s) / 8
+}
+
+func (s Square) Valid() bool {
+	return s >= 0 && s < 64
+}
+
+// offset a square by a number of files/ranks, NoSquare if it falls off the board
+func (s Square) Offset(files, ranks int) Square {
+	if !s.Valid() {
+		return NoSquare
+	}
+	return NewSquare(s.File()+files, s.Rank()+ranks)
+}
+
+func (s Square) String() string {
+	if !s.Valid() {
+		return "-"
+	}
+	return fmt.Sprintf("%c%d", 'a'+s.File(), s.Rank()+1)
+}
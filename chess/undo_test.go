@@ -0,0 +1,208 @@
+package chess_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+	"github.com/val-is/bullet-hell-chess/notation"
+)
+
+// every move two plies deep, and a pass, is taken back exactly
+func TestUnmake(t *testing.T) {
+	var check func(p *chess.Position, depth int)
+	check = func(p *chess.Position, depth int) {
+		before := *p
+		for _, move := range p.LegalMoves() {
+			undo := p.UndoFor(move)
+			if err := p.MakeMove(move); err != nil {
+				t.Fatal(err)
+			}
+			if depth > 1 {
+				check(p, depth-1)
+			}
+			p.Unmake(undo)
+			if *p != before {
+				t.Fatalf("%s then unmaking %s gave %s", notation.FormatFEN(&before), move, notation.FormatFEN(p))
+			}
+		}
+		undo := p.UndoForPass()
+		p.Pass()
+		p.Unmake(undo)
+		if *p != before {
+			t.Fatalf("%s then unmaking a pass gave %s", notation.FormatFEN(&before), notation.FormatFEN(p))
+		}
+	}
+
+	for _, fen := range []string{
+		notation.StartingFEN,
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+		"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+		"r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1",
+		"rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3",
+	} {
+		check(mustParseFEN(t, fen), 2)
+	}
+}
+
+// everything Undo and Redo are supposed to put back
+type gameSnapshot struct {
+	fen         string
+	hash        uint64
+	record      int
+	moves       int
+	repetitions int
+	clock       chess.ClockState
+}
+
+func snapshot(g *chess.Game) gameSnapshot {
+	return gameSnapshot{
+		fen:         notation.FormatFEN(g.Position),
+		hash:        g.Position.Hash(),
+		record:      len(g.Record),
+		moves:       len(g.Moves),
+		repetitions: g.Repetitions(),
+		clock:       g.Clock.State(),
+	}
+}
+
+// plays a random real-time game with passes, pieces taken off and comments, ticking the clock as it goes
+// and pressing it after each move like the scene does, then takes it all back and puts it all back again
+func TestUndoRedo(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for game := 0; game < 20; game++ {
+		g := chess.NewGame(chess.NewStartingPosition())
+		g.Clock = chess.NewClock(chess.TimeControl{Base: time.Hour, Increment: time.Second, Delay: chess.DelayBronstein}, chess.White)
+
+		snapshots := []gameSnapshot{snapshot(g)}
+		for step := 0; step < 60 && !g.Outcome().IsOver(); step++ {
+			if !randomStep(t, r, g) {
+				break
+			}
+			if r.Intn(5) == 0 {
+				g.Comment("a comment")
+			}
+			g.Clock.Tick(time.Duration(r.Intn(3000)) * time.Millisecond)
+			snapshots = append(snapshots, snapshot(g))
+		}
+		steps := len(snapshots) - 1
+
+		for i := steps - 1; i >= 0; i-- {
+			if !g.Undo() {
+				t.Fatalf("game %d: nothing to undo with %d steps left", game, i+1)
+			}
+			if got := snapshot(g); got != snapshots[i] {
+				t.Fatalf("game %d: undoing step %d gave\n%+v\nwant\n%+v", game, i+1, got, snapshots[i])
+			}
+		}
+		if g.Undo() {
+			t.Fatalf("game %d: undid past the start", game)
+		}
+
+		for i := 1; i <= steps; i++ {
+			ok, err := g.Redo()
+			if err != nil {
+				t.Fatalf("game %d: redoing step %d: %s", game, i, err)
+			}
+			if !ok {
+				t.Fatalf("game %d: nothing to redo at step %d", game, i)
+			}
+			if got := snapshot(g); got != snapshots[i] {
+				t.Fatalf("game %d: redoing step %d gave\n%+v\nwant\n%+v", game, i, got, snapshots[i])
+			}
+		}
+		if g.CanRedo() {
+			t.Fatalf("game %d: still something to redo at the end", game)
+		}
+	}
+}
+
+// a move for either side (so sometimes out of turn), a pass or a piece that isn't a king taken off
+// false if there was nothing to do
+func randomStep(t *testing.T, r *rand.Rand, g *chess.Game) bool {
+	switch r.Intn(10) {
+	case 0:
+		mover := g.Position.SideToMove
+		g.Pass()
+		g.Clock.Press(mover)
+		return true
+	case 1:
+		squares := make([]chess.Square, 0)
+		for sq := chess.Square(0); sq < 64; sq++ {
+			if piece := g.Position.Board[sq]; !piece.IsEmpty() && piece.Type != chess.King {
+				squares = append(squares, sq)
+			}
+		}
+		if len(squares) > 0 {
+			g.RemovePiece(squares[r.Intn(len(squares))])
+			return true
+		}
+	}
+
+	color := g.Position.SideToMove
+	if r.Intn(4) == 0 {
+		color = color.Other()
+	}
+	moves := make([]chess.Move, 0)
+	for sq := chess.Square(0); sq < 64; sq++ {
+		if piece := g.Position.Board[sq]; !piece.IsEmpty() && piece.Color == color {
+			moves = append(moves, g.Position.MovesFrom(sq)...)
+		}
+	}
+	if len(moves) == 0 {
+		return false
+	}
+	if err := g.Play(moves[r.Intn(len(moves))]); err != nil {
+		t.Fatal(err)
+	}
+	g.Clock.Press(color)
+	return true
+}
+
+func TestUndoEntryAndRedoClearing(t *testing.T) {
+	g := chess.NewGame(chess.NewStartingPosition())
+	if _, ok := g.UndoEntry(); ok {
+		t.Error("something to undo at the start")
+	}
+	mustPlayGame(t, g, "e2e4", "d2d4")
+
+	// the out of turn move comes with a pass written before it, the entry is still the move
+	entry, ok := g.UndoEntry()
+	if !ok || entry.Kind != chess.RecordMove || entry.Move.String() != "d2d4" {
+		t.Errorf("undo entry is %+v %v, want d2d4", entry, ok)
+	}
+	if len(g.Record) != 3 || g.Record[1].Kind != chess.RecordPass {
+		t.Errorf("record is %+v, want a pass before d2d4", g.Record)
+	}
+	g.Undo()
+	if len(g.Record) != 1 {
+		t.Errorf("record has %d entries after undoing d2d4, want 1", len(g.Record))
+	}
+	if fen := notation.FormatFEN(g.Position); fen != "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1" {
+		t.Errorf("undoing d2d4 gave %s", fen)
+	}
+
+	g.RemovePiece(mustParseSquare(t, "a8"))
+	entry, ok = g.UndoEntry()
+	if !ok || entry.Kind != chess.RecordRemoval || entry.Piece != (chess.Piece{Color: chess.Black, Type: chess.Rook}) {
+		t.Errorf("undo entry is %+v %v, want the rook taken off a8", entry, ok)
+	}
+	if g.CanRedo() {
+		t.Error("taking a piece off left something to redo")
+	}
+	g.Undo()
+	if piece := g.Position.PieceAt(mustParseSquare(t, "a8")); piece != (chess.Piece{Color: chess.Black, Type: chess.Rook}) {
+		t.Errorf("a8 has %v after undoing the removal", piece)
+	}
+	if !g.CanRedo() {
+		t.Fatal("nothing to redo after an undo")
+	}
+	mustPlayGame(t, g, "e7e5")
+	if g.CanRedo() {
+		t.Error("still something to redo after a new move")
+	}
+	if ok, err := g.Redo(); ok || err != nil {
+		t.Errorf("redo after a new move gave %v %v", ok, err)
+	}
+}
@@ -0,0 +1,66 @@
+package chess
+
+// random keys for hashing positions, the same every run so hashes can be compared across games
+var (
+	zobristPieces    [2][7][64]uint64
+	zobristBlack     uint64
+	zobristCastling  [16]uint64
+	zobristEnPassant [8]uint64
+)
+
+func init() {
+	// xorshift, doesn't need to be anything fancier than well spread out
+	state := uint64(0x9e3779b97f4a7c15)
+	next := func() uint64 {
+		state ^= state << 13
+		state ^= state >> 7
+		state ^= state << 17
+		return state
+	}
+	for color := range zobristPieces {
+		for pieceType := range zobristPieces[color] {
+			for sq := range zobristPieces[color][pieceType] {
+				zobristPieces[color][pieceType][sq] = next()
+			}
+		}
+	}
+	zobristBlack = next()
+	for k := range zobristCastling {
+		zobristCastling[k] = next()
+	}
+	for k := range zobristEnPassant {
+		zobristEnPassant[k] = next()
+	}
+}
+
+// identifies a position for repetition, two positions with the same hash are treated as the same
+// en passant only counts when the capture is actually available, like the repetition rule says
+func (p *Position) Hash() uint64 {
+	hash := uint64(0)
+	for sq := Square(0); sq < 64; sq++ {
+		if piece := p.Board[sq]; !piece.IsEmpty() {
+			hash ^= zobristPieces[piece.Color][piece.Type][sq]
+		}
+	}
+	if p.SideToMove == Black {
+		hash ^= zobristBlack
+	}
+	hash ^= zobristCastling[p.Castling]
+	if p.EnPassant != NoSquare && p.canCaptureEnPassant() {
+		hash ^= zobristEnPassant[p.EnPassant.File()]
+	}
+	return hash
+}
+
+func (p *Position) canCaptureEnPassant() bool {
+	for sq := Square(0); sq < 64; sq++ {
+		if piece := p.Board[sq]; piece == (Piece{p.SideToMove, Pawn}) {
+			for _, move := range p.MovesFrom(sq) {
+				if move.To == p.EnPassant {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
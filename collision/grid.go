@@ -0,0 +1,120 @@
+package collision
+
+// uniform grid broad phase over a fixed area, anything outside it is clamped into the edge cells
+// meant to be cleared and refilled every tick, which doesn't allocate once it's warmed up
+// shapes are copied in, so callers can reuse whatever they passed to Insert straight away
+type Grid struct {
+	x, y       float64
+	cellSize   float64
+	cols, rows int
+	cells      [][]int
+	entries    []gridEntry
+	// marks entries already visited in the current query, so ones spanning several cells come up once
+	seen      []uint32
+	queryMark uint32
+}
+
+// what Query hands back. ID is free for the caller to use, e.g. to tell apart several shapes
+// inserted with the same value without boxing anything new
+type Entry struct {
+	Value interface{}
+	ID    uint64
+}
+
+type gridEntry struct {
+	Entry
+	shape  shapeValue
+	bounds AABB
+}
+
+func NewGrid(x, y, w, h, cellSize float64) *Grid {
+	cols := int(w/cellSize) + 1
+	rows := int(h/cellSize) + 1
+	return &Grid{
+		x:        x,
+		y:        y,
+		cellSize: cellSize,
+		cols:     cols,
+		rows:     rows,
+		cells:    make([][]int, cols*rows),
+		entries:  make([]gridEntry, 0),
+		seen:     make([]uint32, 0),
+	}
+}
+
+func (g *Grid) Clear() {
+	for k := range g.cells {
+		g.cells[k] = g.cells[k][:0]
+	}
+	g.entries = g.entries[:0]
+}
+
+func (g *Grid) Len() int {
+	return len(g.entries)
+}
+
+func (g *Grid) cellRange(bounds AABB) (c0, r0, c1, r1 int) {
+	clamp := func(v, max int) int {
+		if v < 0 {
+			return 0
+		}
+		if v > max {
+			return max
+		}
+		return v
+	}
+	c0 = clamp(int((bounds.X-g.x)/g.cellSize), g.cols-1)
+	r0 = clamp(int((bounds.Y-g.y)/g.cellSize), g.rows-1)
+	c1 = clamp(int((bounds.X+bounds.W-g.x)/g.cellSize), g.cols-1)
+	r1 = clamp(int((bounds.Y+bounds.H-g.y)/g.cellSize), g.rows-1)
+	return
+}
+
+func (g *Grid) Insert(shape Shape, value interface{}, id uint64) {
+	index := len(g.entries)
+	stored := valueOf(shape)
+	bounds := stored.bounds()
+	g.entries = append(g.entries, gridEntry{Entry{value, id}, stored, bounds})
+	if len(g.seen) < len(g.entries) {
+		g.seen = append(g.seen, 0)
+	}
+	c0, r0, c1, r1 := g.cellRange(bounds)
+	for r := r0; r <= r1; r++ {
+		for c := c0; c <= c1; c++ {
+			cell := r*g.cols + c
+			g.cells[cell] = append(g.cells[cell], index)
+		}
+	}
+}
+
+// calls found for everything actually touching the shape, stops early if found returns false
+func (g *Grid) Query(shape Shape, found func(entry Entry) bool) {
+	g.queryMark++
+	if g.queryMark == 0 {
+		// wrapped around, old marks could look current
+		for k := range g.seen {
+			g.seen[k] = 0
+		}
+		g.queryMark = 1
+	}
+	query := valueOf(shape)
+	bounds := query.bounds()
+	c0, r0, c1, r1 := g.cellRange(bounds)
+	for r := r0; r <= r1; r++ {
+		for c := c0; c <= c1; c++ {
+			for _, index := range g.cells[r*g.cols+c] {
+				if g.seen[index] == g.queryMark {
+					continue
+				}
+				g.seen[index] = g.queryMark
+				entry := &g.entries[index]
+				if !entry.bounds.Overlaps(bounds) || !intersects(query, entry.shape) {
+					continue
+				}
+				if !found(entry.Entry) {
+					return
+				}
+			}
+		}
+	}
+}
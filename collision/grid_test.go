@@ -0,0 +1,195 @@
+package collision
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestIntersects(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b Shape
+		want bool
+	}{
+		{"circles apart", Circle{0, 0, 1}, Circle{3, 0, 1}, false},
+		{"circles touching", Circle{0, 0, 1}, Circle{2, 0, 1}, true},
+		{"circles overlapping", Circle{0, 0, 2}, Circle{1, 1, 1}, true},
+		{"circle inside circle", Circle{0, 0, 5}, Circle{1, 0, 1}, true},
+		{"circles diagonal miss", Circle{0, 0, 1}, Circle{1.5, 1.5, 1}, false},
+
+		{"circle in box", Circle{5, 5, 1}, AABB{0, 0, 10, 10}, true},
+		{"circle over box edge", Circle{-0.5, 5, 1}, AABB{0, 0, 10, 10}, true},
+		{"circle off box edge", Circle{-2, 5, 1}, AABB{0, 0, 10, 10}, false},
+		{"circle near box corner", Circle{-0.6, -0.6, 1}, AABB{0, 0, 10, 10}, true},
+		{"circle off box corner", Circle{-0.8, -0.8, 1}, AABB{0, 0, 10, 10}, false},
+		{"box then circle", AABB{0, 0, 10, 10}, Circle{11, 5, 1}, true},
+
+		{"boxes overlapping", AABB{0, 0, 2, 2}, AABB{1, 1, 2, 2}, true},
+		{"boxes touching", AABB{0, 0, 2, 2}, AABB{2, 0, 2, 2}, true},
+		{"boxes apart", AABB{0, 0, 2, 2}, AABB{3, 3, 1, 1}, false},
+		{"box inside box", AABB{0, 0, 10, 10}, AABB{4, 4, 1, 1}, true},
+
+		{"capsule through circle", Capsule{-10, 0, 10, 0, 1}, Circle{0, 0, 1}, true},
+		{"capsule past circle", Capsule{-10, 0, 10, 0, 1}, Circle{0, 3, 1}, false},
+		{"capsule end near circle", Capsule{-10, 0, -2, 0, 1}, Circle{0, 0, 1}, true},
+		{"capsule end short of circle", Capsule{-10, 0, -3, 0, 1}, Circle{0, 0, 1}, false},
+		{"point capsule", Capsule{0, 0, 0, 0, 1}, Circle{1.5, 0, 1}, true},
+		{"circle then capsule", Circle{0, 2.5, 1}, Capsule{-10, 0, 10, 0, 2}, true},
+
+		// a fast bullet that jumps clean over the box in one tick still hits it
+		{"capsule across box", Capsule{-10, 5, 20, 5, 0.5}, AABB{0, 0, 10, 10}, true},
+		{"capsule end in box", Capsule{-10, 5, 5, 5, 0}, AABB{0, 0, 10, 10}, true},
+		{"capsule beside box", Capsule{-10, 12, 20, 12, 1}, AABB{0, 0, 10, 10}, false},
+		{"capsule grazing box", Capsule{-10, 11, 20, 11, 1}, AABB{0, 0, 10, 10}, true},
+		{"capsule diagonal past corner", Capsule{12, 0, 20, 8, 1}, AABB{0, 0, 10, 10}, false},
+		{"box then capsule", AABB{0, 0, 10, 10}, Capsule{5, -10, 5, 20, 0}, true},
+
+		{"capsules crossing", Capsule{-5, -5, 5, 5, 0}, Capsule{-5, 5, 5, -5, 0}, true},
+		{"capsules parallel", Capsule{0, 0, 10, 0, 1}, Capsule{0, 3, 10, 3, 1}, false},
+		{"capsules parallel touching", Capsule{0, 0, 10, 0, 1}, Capsule{0, 2, 10, 2, 1}, true},
+		{"capsules end to end", Capsule{0, 0, 10, 0, 1}, Capsule{11.5, 0, 20, 0, 1}, true},
+		{"capsules t apart", Capsule{0, 0, 10, 0, 0.5}, Capsule{5, 2, 5, 10, 0.5}, false},
+
+		{"pointer shapes", &Circle{0, 0, 1}, &AABB{0.5, 0, 1, 1}, true},
+		{"pointer capsule", &Capsule{-10, 0, 10, 0, 1}, Circle{0, 3, 1}, false},
+	}
+	for _, c := range cases {
+		if got := Intersects(c.a, c.b); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+		// the same either way round
+		if got := Intersects(c.b, c.a); got != c.want {
+			t.Errorf("%s reversed: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestBounds(t *testing.T) {
+	cases := []struct {
+		shape Shape
+		want  AABB
+	}{
+		{Circle{5, 5, 2}, AABB{3, 3, 4, 4}},
+		{AABB{1, 2, 3, 4}, AABB{1, 2, 3, 4}},
+		{Capsule{10, 0, 0, 5, 1}, AABB{-1, -1, 12, 7}},
+	}
+	for _, c := range cases {
+		if got := c.shape.Bounds(); got != c.want {
+			t.Errorf("%+v: got %+v, want %+v", c.shape, got, c.want)
+		}
+	}
+}
+
+func randomBullet(r *rand.Rand) Shape {
+	x, y := r.Float64()*screenWidth, r.Float64()*screenHeight
+	if r.Intn(2) == 0 {
+		return Circle{x, y, 1 + r.Float64()*4}
+	}
+	return Capsule{x, y, x + r.Float64()*20 - 10, y + r.Float64()*20 - 10, 1 + r.Float64()*3}
+}
+
+const (
+	screenWidth  = 640
+	screenHeight = 480
+	cellSize     = 32
+)
+
+// whatever the grid finds has to be exactly what checking every shape would find, once each
+func TestGridMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	grid := NewGrid(-64, -64, screenWidth+128, screenHeight+128, cellSize)
+	shapes := make([]Shape, 2000)
+	for round := 0; round < 3; round++ {
+		for k := range shapes {
+			shapes[k] = randomBullet(r)
+		}
+		// some way off the edges, which get clamped into the border cells
+		shapes[0] = Circle{-500, -500, 3}
+		shapes[1] = Capsule{screenWidth + 300, 10, screenWidth + 400, 10, 2}
+		grid.Clear()
+		for k, shape := range shapes {
+			grid.Insert(shape, nil, uint64(k))
+		}
+
+		for q := 0; q < 200; q++ {
+			query := randomBullet(r)
+			if q == 0 {
+				query = Circle{-500, -500, 5}
+			}
+			want := make(map[uint64]bool)
+			for k, shape := range shapes {
+				if Intersects(query, shape) {
+					want[uint64(k)] = true
+				}
+			}
+			got := make(map[uint64]bool)
+			grid.Query(query, func(entry Entry) bool {
+				if got[entry.ID] {
+					t.Fatalf("%+v came up twice", shapes[entry.ID])
+				}
+				got[entry.ID] = true
+				return true
+			})
+			if len(got) != len(want) {
+				t.Fatalf("query %+v: found %d, want %d", query, len(got), len(want))
+			}
+			for id := range want {
+				if !got[id] {
+					t.Fatalf("query %+v missed %+v", query, shapes[id])
+				}
+			}
+		}
+	}
+}
+
+func TestGridQueryStops(t *testing.T) {
+	grid := NewGrid(0, 0, 100, 100, 10)
+	for k := 0; k < 10; k++ {
+		grid.Insert(Circle{50, 50, 5}, nil, uint64(k))
+	}
+	calls := 0
+	grid.Query(Circle{50, 50, 1}, func(entry Entry) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Errorf("query carried on after being told to stop, %d calls", calls)
+	}
+}
+
+// one tick's worth: refill the grid with 5000 bullets and check the cursor against them
+// the %tick metric is how much of a 60 TPS tick that takes
+func BenchmarkGrid5000(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	bullets := make([]Shape, 5000)
+	for k := range bullets {
+		bullets[k] = randomBullet(r)
+	}
+	grid := NewGrid(-64, -64, screenWidth+128, screenHeight+128, cellSize)
+	cursor := &Circle{screenWidth / 2, screenHeight / 2, 3}
+	hits := 0
+	found := func(entry Entry) bool {
+		hits++
+		return true
+	}
+
+	// the first fill grows the grid's slices, after that it's steady state
+	for k, bullet := range bullets {
+		grid.Insert(bullet, nil, uint64(k))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		grid.Clear()
+		for k, bullet := range bullets {
+			grid.Insert(bullet, nil, uint64(k))
+		}
+		cursor.X = float64(i % screenWidth)
+		grid.Query(cursor, found)
+	}
+	perTick := time.Since(start) / time.Duration(b.N)
+	b.ReportMetric(100*float64(perTick)/float64(time.Second/60), "%tick")
+}
@@ -0,0 +1,208 @@
+package collision
+
+import "math"
+
+// anything that can be tested for overlap, coordinates are screen pixels
+type Shape interface {
+	Bounds() AABB
+}
+
+// x, y is the top left corner
+type AABB struct {
+	X, Y, W, H float64
+}
+
+type Circle struct {
+	X, Y, R float64
+}
+
+// circle swept along a line, e.g. where a fast bullet went during a tick
+type Capsule struct {
+	X1, Y1, X2, Y2, R float64
+}
+
+func (a AABB) Bounds() AABB {
+	return a
+}
+
+func (c Circle) Bounds() AABB {
+	return AABB{c.X - c.R, c.Y - c.R, 2 * c.R, 2 * c.R}
+}
+
+func (c Capsule) Bounds() AABB {
+	x, y := math.Min(c.X1, c.X2), math.Min(c.Y1, c.Y2)
+	return AABB{x - c.R, y - c.R, math.Abs(c.X2-c.X1) + 2*c.R, math.Abs(c.Y2-c.Y1) + 2*c.R}
+}
+
+func (a AABB) Contains(px, py float64) bool {
+	return px <= a.X+a.W && px >= a.X && py <= a.Y+a.H && py >= a.Y
+}
+
+func (a AABB) Overlaps(b AABB) bool {
+	return a.X <= b.X+b.W && b.X <= a.X+a.W && a.Y <= b.Y+b.H && b.Y <= a.Y+a.H
+}
+
+// point inside the box closest to p
+func (a AABB) closestPoint(px, py float64) (float64, float64) {
+	return math.Max(a.X, math.Min(px, a.X+a.W)), math.Max(a.Y, math.Min(py, a.Y+a.H))
+}
+
+const (
+	kindUnknown = iota
+	kindCircle
+	kindAABB
+	kindCapsule
+)
+
+type shapeValue struct {
+	kind    int
+	circle  Circle
+	aabb    AABB
+	capsule Capsule
+	// anything else is kept as is and only checked by its bounds
+	other Shape
+}
+
+// pointers to shapes work too, so something that keeps its shape around can hand it over
+// without boxing a fresh copy every tick
+func valueOf(s Shape) shapeValue {
+	switch v := s.(type) {
+	case Circle:
+		return shapeValue{kind: kindCircle, circle: v}
+	case *Circle:
+		return shapeValue{kind: kindCircle, circle: *v}
+	case AABB:
+		return shapeValue{kind: kindAABB, aabb: v}
+	case *AABB:
+		return shapeValue{kind: kindAABB, aabb: *v}
+	case Capsule:
+		return shapeValue{kind: kindCapsule, capsule: v}
+	case *Capsule:
+		return shapeValue{kind: kindCapsule, capsule: *v}
+	}
+	return shapeValue{other: s}
+}
+
+func (v shapeValue) bounds() AABB {
+	switch v.kind {
+	case kindCircle:
+		return v.circle.Bounds()
+	case kindAABB:
+		return v.aabb
+	case kindCapsule:
+		return v.capsule.Bounds()
+	}
+	return v.other.Bounds()
+}
+
+// narrow phase, any pair of the shapes above
+func Intersects(a, b Shape) bool {
+	return intersects(valueOf(a), valueOf(b))
+}
+
+func intersects(va, vb shapeValue) bool {
+	switch va.kind {
+	case kindCircle:
+		switch vb.kind {
+		case kindCircle:
+			return circleCircle(va.circle, vb.circle)
+		case kindAABB:
+			return circleAABB(va.circle, vb.aabb)
+		case kindCapsule:
+			return capsuleCircle(vb.capsule, va.circle)
+		}
+	case kindAABB:
+		switch vb.kind {
+		case kindCircle:
+			return circleAABB(vb.circle, va.aabb)
+		case kindAABB:
+			return va.aabb.Overlaps(vb.aabb)
+		case kindCapsule:
+			return capsuleAABB(vb.capsule, va.aabb)
+		}
+	case kindCapsule:
+		switch vb.kind {
+		case kindCircle:
+			return capsuleCircle(va.capsule, vb.circle)
+		case kindAABB:
+			return capsuleAABB(va.capsule, vb.aabb)
+		case kindCapsule:
+			return capsuleCapsule(va.capsule, vb.capsule)
+		}
+	}
+	// unknown shapes fall back to their bounds
+	return va.bounds().Overlaps(vb.bounds())
+}
+
+func circleCircle(a, b Circle) bool {
+	dx, dy, r := a.X-b.X, a.Y-b.Y, a.R+b.R
+	return dx*dx+dy*dy <= r*r
+}
+
+func circleAABB(c Circle, a AABB) bool {
+	px, py := a.closestPoint(c.X, c.Y)
+	dx, dy := c.X-px, c.Y-py
+	return dx*dx+dy*dy <= c.R*c.R
+}
+
+func capsuleCircle(c Capsule, circle Circle) bool {
+	r := c.R + circle.R
+	return segmentPointDistSq(c.X1, c.Y1, c.X2, c.Y2, circle.X, circle.Y) <= r*r
+}
+
+func capsuleCapsule(a, b Capsule) bool {
+	r := a.R + b.R
+	return segmentSegmentDistSq(a.X1, a.Y1, a.X2, a.Y2, b.X1, b.Y1, b.X2, b.Y2) <= r*r
+}
+
+// the capsule hits the box if its segment gets within r of it: either it crosses the box,
+// or the closest approach is between an endpoint and the box or a box edge and the segment
+func capsuleAABB(c Capsule, a AABB) bool {
+	if a.Contains(c.X1, c.Y1) || a.Contains(c.X2, c.Y2) {
+		return true
+	}
+	r2 := c.R * c.R
+	x2, y2 := a.X+a.W, a.Y+a.H
+	edges := [4][4]float64{
+		{a.X, a.Y, x2, a.Y}, {x2, a.Y, x2, y2},
+		{x2, y2, a.X, y2}, {a.X, y2, a.X, a.Y},
+	}
+	for _, e := range edges {
+		if segmentSegmentDistSq(c.X1, c.Y1, c.X2, c.Y2, e[0], e[1], e[2], e[3]) <= r2 {
+			return true
+		}
+	}
+	return false
+}
+
+func segmentPointDistSq(x1, y1, x2, y2, px, py float64) float64 {
+	dx, dy := x2-x1, y2-y1
+	lenSq := dx*dx + dy*dy
+	t := 0.0
+	if lenSq > 0 {
+		t = math.Max(0, math.Min(1, ((px-x1)*dx+(py-y1)*dy)/lenSq))
+	}
+	cx, cy := x1+t*dx-px, y1+t*dy-py
+	return cx*cx + cy*cy
+}
+
+func segmentsCross(ax1, ay1, ax2, ay2, bx1, by1, bx2, by2 float64) bool {
+	cross := func(ox, oy, px, py, qx, qy float64) float64 {
+		return (px-ox)*(qy-oy) - (py-oy)*(qx-ox)
+	}
+	d1 := cross(bx1, by1, bx2, by2, ax1, ay1)
+	d2 := cross(bx1, by1, bx2, by2, ax2, ay2)
+	d3 := cross(ax1, ay1, ax2, ay2, bx1, by1)
+	d4 := cross(ax1, ay1, ax2, ay2, bx2, by2)
+	return ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0))
+}
+
+func segmentSegmentDistSq(ax1, ay1, ax2, ay2, bx1, by1, bx2, by2 float64) float64 {
+	if segmentsCross(ax1, ay1, ax2, ay2, bx1, by1, bx2, by2) {
+		return 0
+	}
+	return math.Min(
+		math.Min(segmentPointDistSq(ax1, ay1, ax2, ay2, bx1, by1), segmentPointDistSq(ax1, ay1, ax2, ay2, bx2, by2)),
+		math.Min(segmentPointDistSq(bx1, by1, bx2, by2, ax1, ay1), segmentPointDistSq(bx1, by1, bx2, by2, ax2, ay2)),
+	)
+}
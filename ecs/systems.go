@@ -0,0 +1,40 @@
+package ecs
+
+// systems are plain functions over the packed slices, run them in order once per tick and Flush after
+
+// moves everything along its velocity, then applies acceleration, same order as the actor components
+// the old position is kept in the hitbox for sweeping
+func Move(w *World) {
+	for i := range w.Positions {
+		p, v, a := &w.Positions[i], &w.Velocities[i], w.Accelerations[i]
+		hitbox := &w.Hitboxes[i]
+		hitbox.PrevX, hitbox.PrevY = p.X, p.Y
+		p.X += v.X
+		p.Y += v.Y
+		v.X += a.X
+		v.Y += a.Y
+	}
+}
+
+// counts lifetimes down and kills anything that runs out, lifetimes of 0 never run out
+func Expire(w *World) {
+	for i := range w.Lifetimes {
+		if w.Lifetimes[i] <= 0 {
+			continue
+		}
+		w.Lifetimes[i]--
+		if w.Lifetimes[i] == 0 {
+			w.KillAt(i)
+		}
+	}
+}
+
+// kills anything whose hitbox has completely left the area
+func Cull(w *World, minX, minY, maxX, maxY float64) {
+	for i, p := range w.Positions {
+		r := w.Hitboxes[i].Radius
+		if p.X+r < minX || p.X-r > maxX || p.Y+r < minY || p.Y-r > maxY {
+			w.KillAt(i)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package ecs
+
+// data oriented storage for things there are thousands of (bullets), where going through an actor
+// and a handful of interface calls per component per tick adds up
+// every component lives in its own packed slice, and an entity's data sits at the same index in each of them
+
+// handle to an entity, the low half is its slot and the high half a generation that changes
+// whenever the slot's reused, so handles to despawned entities stop working
+type Entity uint64
+
+const NoEntity Entity = 0
+
+func newEntity(slot, generation uint32) Entity {
+	return Entity(uint64(generation)<<32 | uint64(slot))
+}
+
+func (e Entity) slot() uint32 {
+	return uint32(e)
+}
+
+func (e Entity) generation() uint32 {
+	return uint32(e >> 32)
+}
+
+type Vec2 struct {
+	X, Y float64
+}
+
+// circle hitbox, the previous position is kept so it can be swept between ticks
+type Hitbox struct {
+	Radius       float64
+	PrevX, PrevY float64
+}
+
+// everything needed to spawn one entity
+type Body struct {
+	Position     Vec2
+	Velocity     Vec2
+	Acceleration Vec2
+	Radius       float64
+	// ticks until it despawns, 0 for never
+	Lifetime int
+}
+
+type World struct {
+	// packed component data, indexed the same as entities
+	Positions     []Vec2
+	Velocities    []Vec2
+	Accelerations []Vec2
+	Hitboxes      []Hitbox
+	Lifetimes     []int
+
+	entities []Entity
+	// per slot: where its data is in the packed slices, and its current generation
+	dense       []int
+	generations []uint32
+	freeSlots   []uint32
+	// killed entities stay in the packed slices until Flush, so systems can kill while iterating
+	killed int
+}
+
+func NewWorld() *World {
+	return &World{
+		Positions:     make([]Vec2, 0),
+		Velocities:    make([]Vec2, 0),
+		Accelerations: make([]Vec2, 0),
+		Hitboxes:      make([]Hitbox, 0),
+		Lifetimes:     make([]int, 0),
+		entities:      make([]Entity, 0),
+		dense:         make([]int, 0),
+		// generation 0 is never used, so NoEntity can't be alive
+		generations: make([]uint32, 0),
+		freeSlots:   make([]uint32, 0),
+	}
+}
+
+// number of entities in the packed slices, including any killed since the last Flush
+func (w *World) Len() int {
+	return len(w.entities)
+}
+
+// entity whose data is at index i of the packed slices
+func (w *World) EntityAt(i int) Entity {
+	return w.entities[i]
+}
+
+func (w *World) Spawn(body Body) Entity {
+	var slot uint32
+	if n := len(w.freeSlots); n > 0 {
+		slot = w.freeSlots[n-1]
+		w.freeSlots = w.freeSlots[:n-1]
+	} else {
+		slot = uint32(len(w.generations))
+		w.generations = append(w.generations, 0)
+		w.dense = append(w.dense, -1)
+	}
+	w.generations[slot]++
+	entity := newEntity(slot, w.generations[slot])
+
+	w.dense[slot] = len(w.entities)
+	w.entities = append(w.entities, entity)
+	w.Positions = append(w.Positions, body.Position)
+	w.Velocities = append(w.Velocities, body.Velocity)
+	w.Accelerations = append(w.Accelerations, body.Acceleration)
+	w.Hitboxes = append(w.Hitboxes, Hitbox{body.Radius, body.Position.X, body.Position.Y})
+	w.Lifetimes = append(w.Lifetimes, body.Lifetime)
+	return entity
+}
+
+func (w *World) Alive(e Entity) bool {
+	slot := e.slot()
+	return int(slot) < len(w.generations) && w.generations[slot] == e.generation() && w.dense[slot] >= 0
+}
+
+// index of the entity's data in the packed slices
+func (w *World) Index(e Entity) (int, bool) {
+	if !w.Alive(e) {
+		return 0, false
+	}
+	return w.dense[e.slot()], true
+}
+
+// the entity is dead straight away as far as Alive is concerned, its data goes at the next Flush
+// false if it was already dead
+func (w *World) Kill(e Entity) bool {
+	if !w.Alive(e) {
+		return false
+	}
+	w.dense[e.slot()] = -1
+	w.killed++
+	return true
+}
+
+func (w *World) KillAt(i int) bool {
+	return w.Kill(w.entities[i])
+}
+
+// packs the slices back together without the killed entities, keeping everything else in order
+func (w *World) Flush() {
+	if w.killed == 0 {
+		return
+	}
+	kept := 0
+	for i, entity := range w.entities {
+		slot := entity.slot()
+		if w.dense[slot] < 0 {
+			w.freeSlots = append(w.freeSlots, slot)
+			continue
+		}
+		if kept != i {
+			w.entities[kept] = entity
+			w.Positions[kept] = w.Positions[i]
+			w.Velocities[kept] = w.Velocities[i]
+			w.Accelerations[kept] = w.Accelerations[i]
+			w.Hitboxes[kept] = w.Hitboxes[i]
+			w.Lifetimes[kept] = w.Lifetimes[i]
+			w.dense[slot] = kept
+		}
+		kept++
+	}
+	w.entities = w.entities[:kept]
+	w.Positions = w.Positions[:kept]
+	w.Velocities = w.Velocities[:kept]
+	w.Accelerations = w.Accelerations[:kept]
+	w.Hitboxes = w.Hitboxes[:kept]
+	w.Lifetimes = w.Lifetimes[:kept]
+	w.killed = 0
+}
+
+func (w *World) Clear() {
+	for i := range w.entities {
+		w.KillAt(i)
+	}
+	w.Flush()
+}
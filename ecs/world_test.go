@@ -0,0 +1,180 @@
+package ecs
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func body(x, y float64) Body {
+	return Body{Position: Vec2{X: x, Y: y}, Velocity: Vec2{X: 1, Y: -1}, Radius: 2}
+}
+
+func TestSpawn(t *testing.T) {
+	w := NewWorld()
+	a := w.Spawn(body(1, 2))
+	b := w.Spawn(body(3, 4))
+
+	if a == NoEntity || b == NoEntity || a == b {
+		t.Fatalf("spawned %v and %v", a, b)
+	}
+	if w.Len() != 2 {
+		t.Fatalf("len %d, want 2", w.Len())
+	}
+	for _, tc := range []struct {
+		e    Entity
+		x, y float64
+	}{{a, 1, 2}, {b, 3, 4}} {
+		if !w.Alive(tc.e) {
+			t.Errorf("%v isn't alive", tc.e)
+		}
+		i, ok := w.Index(tc.e)
+		if !ok {
+			t.Fatalf("no index for %v", tc.e)
+		}
+		if w.EntityAt(i) != tc.e {
+			t.Errorf("entity at %d is %v, want %v", i, w.EntityAt(i), tc.e)
+		}
+		if p := w.Positions[i]; p.X != tc.x || p.Y != tc.y {
+			t.Errorf("%v is at %v, want (%v, %v)", tc.e, p, tc.x, tc.y)
+		}
+		if h := w.Hitboxes[i]; h.Radius != 2 || h.PrevX != tc.x || h.PrevY != tc.y {
+			t.Errorf("%v has hitbox %+v", tc.e, h)
+		}
+	}
+	if w.Alive(NoEntity) {
+		t.Error("NoEntity is alive")
+	}
+}
+
+func TestKillAndFlush(t *testing.T) {
+	w := NewWorld()
+	entities := make([]Entity, 5)
+	for i := range entities {
+		entities[i] = w.Spawn(body(float64(i), 0))
+	}
+
+	if !w.Kill(entities[1]) || !w.Kill(entities[3]) {
+		t.Fatal("kill of a live entity returned false")
+	}
+	if w.Kill(entities[1]) {
+		t.Error("killing the same entity twice returned true")
+	}
+	if w.Alive(entities[1]) || w.Alive(entities[3]) {
+		t.Error("killed entities are still alive before the flush")
+	}
+	// the data stays put until the flush
+	if w.Len() != 5 {
+		t.Errorf("len %d before flush, want 5", w.Len())
+	}
+
+	w.Flush()
+	if w.Len() != 3 {
+		t.Fatalf("len %d after flush, want 3", w.Len())
+	}
+	// the survivors keep their order and their data moves with them
+	for n, want := range []int{0, 2, 4} {
+		e := entities[want]
+		i, ok := w.Index(e)
+		if !ok {
+			t.Fatalf("%v died in the flush", e)
+		}
+		if i != n {
+			t.Errorf("%v is at %d, want %d", e, i, n)
+		}
+		if w.Positions[i].X != float64(want) {
+			t.Errorf("%v has x %v, want %v", e, w.Positions[i].X, want)
+		}
+	}
+
+	w.Clear()
+	if w.Len() != 0 {
+		t.Errorf("len %d after clear", w.Len())
+	}
+	for _, e := range entities {
+		if w.Alive(e) {
+			t.Errorf("%v is alive after clear", e)
+		}
+	}
+}
+
+func TestStaleGeneration(t *testing.T) {
+	w := NewWorld()
+	old := w.Spawn(body(0, 0))
+	w.Kill(old)
+	w.Flush()
+
+	reused := w.Spawn(body(5, 5))
+	if reused.slot() != old.slot() {
+		t.Fatalf("slot %d wasn't reused, got %d", old.slot(), reused.slot())
+	}
+	if reused == old {
+		t.Fatal("reused slot kept the same generation")
+	}
+
+	if w.Alive(old) {
+		t.Error("stale handle is alive")
+	}
+	if _, ok := w.Index(old); ok {
+		t.Error("stale handle has an index")
+	}
+	if w.Kill(old) {
+		t.Error("stale handle killed the entity now in its slot")
+	}
+	if !w.Alive(reused) {
+		t.Error("the new entity died from a kill through the stale handle")
+	}
+}
+
+func TestSystems(t *testing.T) {
+	w := NewWorld()
+	moving := w.Spawn(Body{Position: Vec2{10, 10}, Velocity: Vec2{2, 0}, Acceleration: Vec2{0, 1}, Radius: 1})
+	expiring := w.Spawn(Body{Position: Vec2{10, 10}, Radius: 1, Lifetime: 2})
+	leaving := w.Spawn(Body{Position: Vec2{98, 50}, Velocity: Vec2{5, 0}, Radius: 1})
+
+	for tick := 0; tick < 2; tick++ {
+		Move(w)
+		Expire(w)
+		Cull(w, 0, 0, 100, 100)
+		w.Flush()
+	}
+
+	i, ok := w.Index(moving)
+	if !ok {
+		t.Fatal("moving entity died")
+	}
+	// velocity's applied before acceleration, same as ComponentVelocity
+	if p := w.Positions[i]; p.X != 14 || p.Y != 11 {
+		t.Errorf("moving entity is at %v, want (14, 11)", p)
+	}
+	if h := w.Hitboxes[i]; h.PrevX != 12 || h.PrevY != 10 {
+		t.Errorf("moving entity's previous position is (%v, %v), want (12, 10)", h.PrevX, h.PrevY)
+	}
+	if w.Alive(expiring) {
+		t.Error("entity outlived its lifetime")
+	}
+	if w.Alive(leaving) {
+		t.Error("entity that left the area wasn't culled")
+	}
+}
+
+// the systems on their own, the comparison against actors is in the engine's benchmarks
+func BenchmarkSystems5000(b *testing.B) {
+	w := NewWorld()
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		w.Spawn(Body{
+			Position: Vec2{r.Float64() * 640, r.Float64() * 480},
+			Velocity: Vec2{(r.Float64() - 0.5) * 1e-6, (r.Float64() - 0.5) * 1e-6},
+			Radius:   3,
+		})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Move(w)
+		Expire(w)
+		Cull(w, 0, 0, 640, 480)
+		w.Flush()
+	}
+}
@@ -14,28 +14,35 @@ type Actor struct {
 }
 
 type ActorInterface interface {
-	Update() error
-	Draw(screen *ebiten.Image, renderLayer RenderLayer) error
+	Update(sim SimClockInterface) error
+	Draw(screen *ebiten.Image, renderLayer RenderLayer, sim SimClockInterface) error
 	GetComponent(componentType string) (ComponentInterface, error)
+	GetComponents(componentType string) []ComponentInterface
 	GetActorType() string
 	GetId() string
+	GetParentScene() SceneInterface
 }
 
-func (a *Actor) Update() error {
+func (a *Actor) Update(sim SimClockInterface) error {
 	for k := range a.components {
-		if err := a.components[k].Update(); err != nil {
+		if err := a.components[k].Update(sim); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (a *Actor) Draw(screen *ebiten.Image, renderLayer RenderLayer) error {
+func (a *Actor) Draw(screen *ebiten.Image, renderLayer RenderLayer, sim SimClockInterface) error {
 	for k := range a.components {
-		if a.components[k].GetComponentType() == ComponentTypeDrawable {
-			if err := a.components[k].(ComponentDrawableInterface).Draw(screen, renderLayer); err != nil {
-				return err
-			}
+		if a.components[k].GetComponentType() != ComponentTypeDrawable {
+			continue
+		}
+		drawable, ok := a.components[k].(ComponentDrawableInterface)
+		if !ok {
+			return wrongComponentError(a, a.components[k], "ComponentDrawableInterface")
+		}
+		if err := drawable.Draw(screen, renderLayer, sim); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -50,6 +57,34 @@ func (a *Actor) GetComponent(componentType string) (ComponentInterface, error) {
 	return nil, fmt.Errorf("component of type %s on %s not found", componentType, a.id)
 }
 
+// every component of the type, in the order they were added
+// most actors only have one of each, but e.g. chess pieces have a drawable for the piece and one for its move markers
+func (a *Actor) GetComponents(componentType string) []ComponentInterface {
+	found := make([]ComponentInterface, 0)
+	for k := range a.components {
+		if a.components[k].GetComponentType() == componentType {
+			found = append(found, a.components[k])
+		}
+	}
+	return found
+}
+
+// for the typed getters (GetWorldly and co.), when something's been added under a component type
+// without actually being that kind of component
+func wrongComponentError(actor ActorInterface, component ComponentInterface, want string) error {
+	return fmt.Errorf("component of type %s on %s is a %T, not a %s",
+		component.GetComponentType(), actor.GetId(), component, want)
+}
+
+// resets every component that has per-life state, used when the actor goes back into a pool
+func (a *Actor) Reset() {
+	for k := range a.components {
+		if resettable, ok := a.components[k].(ResettableInterface); ok {
+			resettable.Reset()
+		}
+	}
+}
+
 func (a *Actor) GetActorType() string {
 	return a.actorType
 }
@@ -57,3 +92,7 @@ func (a *Actor) GetActorType() string {
 func (a *Actor) GetId() string {
 	return a.id
 }
+
+func (a *Actor) GetParentScene() SceneInterface {
+	return a.parentScene
+}
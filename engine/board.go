@@ -1,5 +1,13 @@
 package engine
 
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/val-is/bullet-hell-chess/chess"
+	"github.com/val-is/bullet-hell-chess/notation"
+)
+
 const (
 	BoardWidth        = 400.0
 	BoardSpriteWidth  = 180.0
@@ -33,7 +41,7 @@ func NewActorBoard(parentScene SceneInterface, id, imagePath string) (ActorInter
 		components:  make([]ComponentInterface, 0),
 	}
 
-	sprite, err := NewBasicSpriteFromPath(imagePath)
+	sprite, err := GetSpriteFromPath(imagePath)
 	if err != nil {
 		return nil, err
 	}
@@ -51,23 +59,55 @@ func NewActorBoard(parentScene SceneInterface, id, imagePath string) (ActorInter
 	}
 	actor.components = append(actor.components, worldly)
 
+	// only used for click input, drag input goes through the pieces themselves
+	clickableComp, err := NewComponentClickable(&actor)
+	if err != nil {
+		return nil, err
+	}
+	clickableComp.AddStateListener(MouseStatePressed, func() error {
+		scene, ok := parentScene.(ChessSceneInterface)
+		if !ok || scene.GetSettings().InputMode != InputModeClick {
+			return nil
+		}
+		mx, my := ebiten.CursorPosition()
+		square, onBoard := ScreenToBoardSquare(float64(mx), float64(my))
+		if !onBoard {
+			scene.ClearSelection()
+			return nil
+		}
+		return scene.ClickSquare(square)
+	})
+	actor.components = append(actor.components, clickableComp)
+
 	return &actor, nil
 }
 
-// helpers for notation
-func AlgebraicToNative(row, column int) BoardSquare {
-	// algebraic is 1 indexed, bottom left -> 1,1
-	return BoardSquare{row - 1, 8 - column}
+// helpers for notation, squares by name ("e4") rather than by where they are on screen
+func ParseBoardSquare(name string) (BoardSquare, error) {
+	square, err := notation.ParseSquare(name)
+	if err != nil {
+		return BoardSquare{}, err
+	}
+	return SquareToNative(square), nil
+}
+
+func BoardSquareName(square BoardSquare) string {
+	return notation.FormatSquare(NativeToSquare(square))
+}
+
+// native squares count rows down from the top of the screen, the rules core counts ranks up from white's side
+func NativeToSquare(square BoardSquare) chess.Square {
+	return chess.NewSquare(square[0], 7-square[1])
 }
 
-func NativeToAlgebraic(square BoardSquare) (row, column int) {
-	return square[0] + 1, 8 - square[1]
+func SquareToNative(square chess.Square) BoardSquare {
+	return BoardSquare{square.File(), 7 - square.Rank()}
 }
 
 // get drawing coordinates for pieces/markers/anything to be centered in a square
 func GetBoardDrawingCoords(square BoardSquare, w, h float64) (x, y float64) {
-	boardX := BoardConversionFactor*BoardPixelBorder + (ScreenWidth-BoardWidth)/2.0
-	boardY := BoardConversionFactor*BoardPixelBorder + (ScreenHeight-BoardHeight)/2.0
+	boardX := BoardPieceOffsetX
+	boardY := BoardPieceOffsetY
 
 	paddingX := (BoardCellWidth - w) / 2.0
 	paddingY := (BoardCellHeight - h) / 2.0
@@ -77,3 +117,13 @@ func GetBoardDrawingCoords(square BoardSquare, w, h float64) (x, y float64) {
 
 	return boardX + paddingX + cellPosX, boardY + paddingY + cellPosY
 }
+
+// inverse of GetBoardDrawingCoords, which square a point on screen is over (if any)
+func ScreenToBoardSquare(x, y float64) (BoardSquare, bool) {
+	col := int(math.Floor((x - BoardPieceOffsetX) / BoardCellWidth))
+	row := int(math.Floor((y - BoardPieceOffsetY) / BoardCellHeight))
+	if col < 0 || col > 7 || row < 0 || row > 7 {
+		return BoardSquare{}, false
+	}
+	return BoardSquare{col, row}, true
+}
@@ -0,0 +1,311 @@
+package engine
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/val-is/bullet-hell-chess/collision"
+	"github.com/val-is/bullet-hell-chess/pattern"
+)
+
+var BulletColor = color.RGBA{0xff, 0x40, 0x60, 0xff}
+
+// component that moves its actor's worldly every tick
+const ComponentTypeVelocity = "component-velocity"
+
+type ComponentVelocity struct {
+	Component
+	vx, vy float64
+	ax, ay float64
+}
+
+type ComponentVelocityInterface interface {
+	ComponentInterface
+	GetVelocity() (vx, vy float64)
+	SetVelocity(vx, vy float64)
+	GetAcceleration() (ax, ay float64)
+	SetAcceleration(ax, ay float64)
+}
+
+// speeds are in pixels per tick, acceleration in pixels per tick per tick
+func NewComponentVelocity(parent ActorInterface, vx, vy, ax, ay float64) (ComponentVelocityInterface, error) {
+	return &ComponentVelocity{
+		Component: Component{parent, ComponentTypeVelocity},
+		vx:        vx,
+		vy:        vy,
+		ax:        ax,
+		ay:        ay,
+	}, nil
+}
+
+func GetVelocity(actor ActorInterface) (ComponentVelocityInterface, error) {
+	component, err := actor.GetComponent(ComponentTypeVelocity)
+	if err != nil {
+		return nil, err
+	}
+	velocity, ok := component.(ComponentVelocityInterface)
+	if !ok {
+		return nil, wrongComponentError(actor, component, "ComponentVelocityInterface")
+	}
+	return velocity, nil
+}
+
+func (c *ComponentVelocity) GetVelocity() (vx, vy float64) {
+	return c.vx, c.vy
+}
+
+func (c *ComponentVelocity) SetVelocity(vx, vy float64) {
+	c.vx = vx
+	c.vy = vy
+}
+
+func (c *ComponentVelocity) GetAcceleration() (ax, ay float64) {
+	return c.ax, c.ay
+}
+
+func (c *ComponentVelocity) SetAcceleration(ax, ay float64) {
+	c.ax = ax
+	c.ay = ay
+}
+
+func (c *ComponentVelocity) Update(sim SimClockInterface) error {
+	worldly, err := GetWorldly(c.parentActor)
+	if err != nil {
+		return err
+	}
+	x, y := worldly.GetPosition()
+	worldly.MovePosition(x+c.vx, y+c.vy)
+	c.vx += c.ax
+	c.vy += c.ay
+	return nil
+}
+
+// component for things that fly around and hurt, takes its actor out of the scene once it leaves the screen
+// or runs out of lifetime
+const ComponentTypeProjectile = "component-projectile"
+
+type ComponentProjectile struct {
+	Component
+	radius float64
+	// ticks left, only counts down if it started above 0
+	lifetime int
+	// where the center was last tick, so the hitbox covers the whole path and fast bullets can't skip over things
+	lastX, lastY float64
+	hasLast      bool
+	// reused every tick so registering it doesn't allocate
+	shape collision.Capsule
+}
+
+type ComponentProjectileInterface interface {
+	ComponentInterface
+	ResettableInterface
+	GetRadius() float64
+	SetRadius(radius float64)
+	SetLifetime(lifetime int)
+	GetCenter() (x, y float64, err error)
+	GetShape() (*collision.Capsule, error)
+}
+
+func NewComponentProjectile(parent ActorInterface, radius float64, lifetime int) (ComponentProjectileInterface, error) {
+	return &ComponentProjectile{
+		Component: Component{parent, ComponentTypeProjectile},
+		radius:    radius,
+		lifetime:  lifetime,
+	}, nil
+}
+
+func GetProjectile(actor ActorInterface) (ComponentProjectileInterface, error) {
+	component, err := actor.GetComponent(ComponentTypeProjectile)
+	if err != nil {
+		return nil, err
+	}
+	projectile, ok := component.(ComponentProjectileInterface)
+	if !ok {
+		return nil, wrongComponentError(actor, component, "ComponentProjectileInterface")
+	}
+	return projectile, nil
+}
+
+func (c *ComponentProjectile) Reset() {
+	c.lifetime = 0
+	c.hasLast = false
+}
+
+func (c *ComponentProjectile) GetRadius() float64 {
+	return c.radius
+}
+
+func (c *ComponentProjectile) SetRadius(radius float64) {
+	c.radius = radius
+}
+
+func (c *ComponentProjectile) SetLifetime(lifetime int) {
+	c.lifetime = lifetime
+}
+
+func (c *ComponentProjectile) GetCenter() (x, y float64, err error) {
+	worldly, err := GetWorldly(c.parentActor)
+	if err != nil {
+		return 0, 0, err
+	}
+	bbx, bby, bbw, bbh := worldly.GetBoundingBox()
+	return bbx + bbw/2, bby + bbh/2, nil
+}
+
+// a capsule from last tick's center to this one, or just a circle (zero length capsule) on the first tick
+// the shape is reused, so it's only good until the next update
+func (c *ComponentProjectile) GetShape() (*collision.Capsule, error) {
+	x, y, err := c.GetCenter()
+	if err != nil {
+		return nil, err
+	}
+	lastX, lastY := x, y
+	if c.hasLast {
+		lastX, lastY = c.lastX, c.lastY
+	}
+	c.shape = collision.Capsule{X1: lastX, Y1: lastY, X2: x, Y2: y, R: c.radius}
+	return &c.shape, nil
+}
+
+func (c *ComponentProjectile) Update(sim SimClockInterface) error {
+	worldly, err := GetWorldly(c.parentActor)
+	if err != nil {
+		return err
+	}
+	x, y, w, h := worldly.GetBoundingBox()
+	scene := c.parentActor.GetParentScene()
+	if x+w < 0 || x > ScreenWidth || y+h < 0 || y > ScreenHeight {
+		return scene.RemoveActor(c.parentActor.GetId())
+	}
+	if c.lifetime > 0 {
+		c.lifetime--
+		if c.lifetime == 0 {
+			return scene.RemoveActor(c.parentActor.GetId())
+		}
+	}
+
+	shape, err := c.GetShape()
+	if err != nil {
+		return err
+	}
+	scene.AddCollider(shape, c.parentActor, 0)
+	c.lastX, c.lastY, c.hasLast = x+w/2, y+h/2, true
+	return nil
+}
+
+const ActorTypeBullet = "actor-bullet"
+
+// drawable, worldly, velocity, projectile, then one emitter per child pattern
+const bulletBaseComponents = 4
+
+// x, y is where the bullet's center starts, each child pattern gets fired from the bullet as it flies
+// bullets come out of the scene's pool when there's one free, so this usually doesn't allocate
+func NewActorBullet(parentScene SceneInterface, x, y, radius, vx, vy, ax, ay float64,
+	lifetime int, children []pattern.Emitter) (ActorInterface, error) {
+
+	sprite, err := GetCircleSprite(int(math.Ceil(radius)), BulletColor)
+	if err != nil {
+		return nil, err
+	}
+
+	if pooled, ok := parentScene.GetPool(ActorTypeBullet).Get(); ok {
+		actor, ok := pooled.(*Actor)
+		if !ok {
+			return nil, fmt.Errorf("bullet pool has a %T in it", pooled)
+		}
+		if err := resetBullet(actor, sprite, x, y, radius, vx, vy, ax, ay, lifetime); err != nil {
+			return nil, err
+		}
+		if err := setBulletChildren(actor, children); err != nil {
+			return nil, err
+		}
+		return actor, nil
+	}
+
+	actor := Actor{
+		parentScene: parentScene,
+		actorType:   ActorTypeBullet,
+		id:          NewId("bullet-"),
+		components:  make([]ComponentInterface, 0, bulletBaseComponents+len(children)),
+	}
+
+	spriteComp, err := NewComponentDrawable(&actor, sprite, RenderLayerBullet)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, spriteComp)
+
+	worldly, err := NewComponentWorldly(&actor, x-radius, y-radius, 2*radius, 2*radius, 0)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, worldly)
+
+	velocity, err := NewComponentVelocity(&actor, vx, vy, ax, ay)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, velocity)
+
+	projectile, err := NewComponentProjectile(&actor, radius, lifetime)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, projectile)
+
+	if err := setBulletChildren(&actor, children); err != nil {
+		return nil, err
+	}
+
+	return &actor, nil
+}
+
+func resetBullet(actor *Actor, sprite SpriteInterface, x, y, radius, vx, vy, ax, ay float64, lifetime int) error {
+	drawable, err := GetDrawable(actor)
+	if err != nil {
+		return err
+	}
+	drawable.SetSprite(sprite)
+	worldly, err := GetWorldly(actor)
+	if err != nil {
+		return err
+	}
+	worldly.SetPosition(x-radius, y-radius)
+	worldly.SetScale(2*radius, 2*radius)
+	velocity, err := GetVelocity(actor)
+	if err != nil {
+		return err
+	}
+	velocity.SetVelocity(vx, vy)
+	velocity.SetAcceleration(ax, ay)
+	projectile, err := GetProjectile(actor)
+	if err != nil {
+		return err
+	}
+	projectile.SetRadius(radius)
+	projectile.SetLifetime(lifetime)
+	return nil
+}
+
+// emitters left over from a recycled bullet's earlier children are still sitting past the end of the slice,
+// so those get reused and new ones are only made when there aren't enough
+func setBulletChildren(actor *Actor, children []pattern.Emitter) error {
+	actor.components = actor.components[:bulletBaseComponents]
+	for _, child := range children {
+		n := len(actor.components)
+		if n < cap(actor.components) {
+			if emitter, ok := actor.components[:n+1][n].(ComponentEmitterInterface); ok {
+				emitter.SetPattern(child)
+				actor.components = actor.components[:n+1]
+				continue
+			}
+		}
+		emitter, err := NewComponentEmitter(actor, child, false)
+		if err != nil {
+			return err
+		}
+		actor.components = append(actor.components, emitter)
+	}
+	return nil
+}
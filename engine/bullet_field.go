@@ -0,0 +1,166 @@
+package engine
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/val-is/bullet-hell-chess/collision"
+	"github.com/val-is/bullet-hell-chess/ecs"
+)
+
+// plain bullets (no child patterns) all live in one actor, packed into an ecs world rather than being
+// an actor each. bullets that fire patterns of their own are still actors, see NewActorBullet
+const ComponentTypeBulletField = "component-bullet-field"
+
+type ComponentBulletField struct {
+	Component
+	world *ecs.World
+	// reused for every bullet's collider, the scene copies it
+	shape collision.Capsule
+}
+
+type ComponentBulletFieldInterface interface {
+	ComponentInterface
+	GetWorld() *ecs.World
+	Spawn(body ecs.Body) ecs.Entity
+	// false if the bullet's already gone
+	Kill(bullet ecs.Entity) bool
+}
+
+func NewComponentBulletField(parent ActorInterface) (ComponentBulletFieldInterface, error) {
+	return &ComponentBulletField{
+		Component: Component{parent, ComponentTypeBulletField},
+		world:     ecs.NewWorld(),
+	}, nil
+}
+
+func GetBulletField(actor ActorInterface) (ComponentBulletFieldInterface, error) {
+	component, err := actor.GetComponent(ComponentTypeBulletField)
+	if err != nil {
+		return nil, err
+	}
+	field, ok := component.(ComponentBulletFieldInterface)
+	if !ok {
+		return nil, wrongComponentError(actor, component, "ComponentBulletFieldInterface")
+	}
+	return field, nil
+}
+
+func (c *ComponentBulletField) GetWorld() *ecs.World {
+	return c.world
+}
+
+func (c *ComponentBulletField) Spawn(body ecs.Body) ecs.Entity {
+	return c.world.Spawn(body)
+}
+
+func (c *ComponentBulletField) Kill(bullet ecs.Entity) bool {
+	return c.world.Kill(bullet)
+}
+
+// same steps a bullet actor's components go through, just for every bullet at once
+func (c *ComponentBulletField) Update(sim SimClockInterface) error {
+	ecs.Move(c.world)
+	ecs.Cull(c.world, 0, 0, ScreenWidth, ScreenHeight)
+	ecs.Expire(c.world)
+	c.world.Flush()
+
+	scene := c.parentActor.GetParentScene()
+	for i, position := range c.world.Positions {
+		hitbox := c.world.Hitboxes[i]
+		c.shape = collision.Capsule{X1: hitbox.PrevX, Y1: hitbox.PrevY, X2: position.X, Y2: position.Y, R: hitbox.Radius}
+		scene.AddCollider(&c.shape, c.parentActor, uint64(c.world.EntityAt(i)))
+	}
+	return nil
+}
+
+// drawable for the whole field, one circle sprite per bullet
+type ComponentBulletFieldSprites struct {
+	ComponentDrawable
+}
+
+type ComponentBulletFieldSpritesInterface interface {
+	ComponentDrawableInterface
+}
+
+func NewComponentBulletFieldSprites(parent ActorInterface, renderLayer RenderLayer) (ComponentBulletFieldSpritesInterface, error) {
+	component := ComponentBulletFieldSprites{
+		ComponentDrawable: ComponentDrawable{
+			Component:   Component{parent, ComponentTypeDrawable},
+			renderLayer: renderLayer,
+			active:      true,
+		},
+	}
+	return &component, nil
+}
+
+func (c *ComponentBulletFieldSprites) Draw(screen *ebiten.Image, renderLayer RenderLayer, sim SimClockInterface) error {
+	if !c.CheckIfDrawable(renderLayer) {
+		return nil
+	}
+	field, err := GetBulletField(c.parentActor)
+	if err != nil {
+		return err
+	}
+	world := field.GetWorld()
+	// bullets mostly share a few sizes, so only look the sprite up again when the size changes
+	spriteRadius := -1
+	var sprite SpriteInterface
+	alpha := sim.GetAlpha()
+	for i, position := range world.Positions {
+		hitbox := world.Hitboxes[i]
+		radius := hitbox.Radius
+		x, y := Lerp(hitbox.PrevX, position.X, alpha), Lerp(hitbox.PrevY, position.Y, alpha)
+		if r := int(math.Ceil(radius)); r != spriteRadius {
+			if sprite, err = GetCircleSprite(r, BulletColor); err != nil {
+				return err
+			}
+			spriteRadius = r
+		}
+		if err := sprite.Draw(screen, x-radius, y-radius, 2*radius, 2*radius, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const (
+	ActorTypeBulletField = "actor-bullet-field"
+	BulletFieldId        = "bullet-field"
+)
+
+// the scene's bullet field if it has one
+func FindBulletField(scene SceneInterface) (ComponentBulletFieldInterface, bool, error) {
+	fields := scene.GetActorsType(ActorTypeBulletField)
+	if len(fields) == 0 {
+		return nil, false, nil
+	}
+	field, err := GetBulletField(fields[0])
+	if err != nil {
+		return nil, false, err
+	}
+	return field, true, nil
+}
+
+func NewActorBulletField(parentScene SceneInterface) (ActorInterface, error) {
+	actor := Actor{
+		parentScene: parentScene,
+		actorType:   ActorTypeBulletField,
+		id:          BulletFieldId,
+		components:  make([]ComponentInterface, 0),
+	}
+
+	field, err := NewComponentBulletField(&actor)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, field)
+
+	sprites, err := NewComponentBulletFieldSprites(&actor, RenderLayerBullet)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, sprites)
+
+	return &actor, nil
+}
@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/val-is/bullet-hell-chess/ecs"
+)
+
+// a scene with n bullets that barely move, so none of them get culled while it's timed,
+// either as an actor each or packed into the bullet field
+func benchmarkBullets(b *testing.B, n int, packed bool) {
+	scene, err := NewScene()
+	if err != nil {
+		b.Fatal(err)
+	}
+	field, err := NewActorBulletField(scene)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := scene.AddActor(field); err != nil {
+		b.Fatal(err)
+	}
+	fieldComponent, err := GetBulletField(field)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < n; i++ {
+		x, y := r.Float64()*ScreenWidth, r.Float64()*ScreenHeight
+		vx, vy := (r.Float64()-0.5)*1e-6, (r.Float64()-0.5)*1e-6
+		if packed {
+			fieldComponent.Spawn(ecs.Body{Position: ecs.Vec2{X: x, Y: y}, Velocity: ecs.Vec2{X: vx, Y: vy}, Radius: 3})
+			continue
+		}
+		bullet, err := NewActorBullet(scene, x, y, 3, vx, vy, 0, 0, 0, nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := scene.AddActor(bullet); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	sim, err := NewSimClock()
+	if err != nil {
+		b.Fatal(err)
+	}
+	// the scene swaps between two collider grids, give both a tick to grow
+	for i := 0; i < 2; i++ {
+		if err := scene.Update(sim); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := scene.Update(sim); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBulletsActors(b *testing.B) {
+	for _, n := range []int{1000, 5000, 20000} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) { benchmarkBullets(b, n, false) })
+	}
+}
+
+func BenchmarkBulletsECS(b *testing.B) {
+	for _, n := range []int{1000, 5000, 20000} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) { benchmarkBullets(b, n, true) })
+	}
+}
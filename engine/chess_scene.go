@@ -0,0 +1,324 @@
+package engine
+
+import (
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+	"github.com/val-is/bullet-hell-chess/notation"
+)
+
+// how long the final position stays up before moving on to the results
+const GameOverDelayTicks = 120
+
+// details of a move that's just been committed
+type MoveEvent struct {
+	Move     chess.Move
+	Piece    chess.Piece
+	Captured chess.Piece
+	// whether the move put the opponent in check
+	Check bool
+}
+
+type MoveListener func(event MoveEvent) error
+
+// scene that carries the rules-side state of a chess game alongside its actors
+type ChessScene struct {
+	Scene
+	settings      GameSettings
+	game          *chess.Game
+	clock         *chess.Clock
+	outcome       chess.Outcome
+	gameOverTicks int
+	// square of the selected piece in click input mode
+	selection     BoardSquare
+	hasSelection  bool
+	hits          map[BoardSide]int
+	moveListeners []MoveListener
+	started       time.Time
+	// a takeback someone's asked for, only good until something else happens, see GetTakebackRequest
+	takebackFrom   BoardSide
+	takebackRecord int
+	takebackAsked  bool
+}
+
+type ChessSceneInterface interface {
+	SceneInterface
+	GetSettings() GameSettings
+	GetTurn() BoardSide
+	IsTurn(side BoardSide) bool
+	GetCheckedSide() (BoardSide, bool)
+	GetGame() *chess.Game
+	GetClock() *chess.Clock
+	GetPosition() *chess.Position
+	CommitMove(move chess.Move) error
+	AddMoveListener(listener MoveListener)
+	GetOutcome() chess.Outcome
+	SetOutcome(outcome chess.Outcome)
+
+	GetHits(side BoardSide) int
+	RecordHit(side BoardSide) error
+
+	GetPGN() string
+	SavePGN(dir string) (string, error)
+
+	Undo() error
+	Redo() error
+	RequestTakeback() error
+	GetTakebackRequest() (BoardSide, bool)
+	AnswerTakeback(accept bool) error
+
+	GetSelection() (BoardSquare, bool)
+	ClearSelection()
+	ClickSquare(square BoardSquare) error
+}
+
+func NewChessScene(position *chess.Position, settings GameSettings) (ChessSceneInterface, error) {
+	game := chess.NewGame(position)
+	game.Clock = chess.NewClock(settings.TimeControl, position.SideToMove)
+	s := ChessScene{
+		Scene:    newBaseScene(),
+		settings: settings,
+		game:     game,
+		clock:    game.Clock,
+		hits:     make(map[BoardSide]int),
+
+		moveListeners: make([]MoveListener, 0),
+		outcome:       game.Outcome(),
+		started:       time.Now(),
+	}
+
+	return &s, nil
+}
+
+func (s *ChessScene) GetSettings() GameSettings {
+	return s.settings
+}
+
+// only meaningful with turns on, in real-time it's just whoever moved last's opponent
+func (s *ChessScene) GetTurn() BoardSide {
+	return ColorToBoardSide(s.game.Position.SideToMove)
+}
+
+// whether pieces of a side can be picked up right now
+func (s *ChessScene) IsTurn(side BoardSide) bool {
+	if s.outcome.IsOver() {
+		return false
+	}
+	if s.settings.Mode == GameModeRealtime {
+		// a side in check has to get out of it before the other side can move again
+		if checked, inCheck := s.GetCheckedSide(); inCheck {
+			return side == checked
+		}
+		return true
+	}
+	return s.GetTurn() == side
+}
+
+// whichever side is in check, if either is. only one side can be at a time
+func (s *ChessScene) GetCheckedSide() (BoardSide, bool) {
+	for _, side := range []BoardSide{BoardSideWhite, BoardSideBlack} {
+		if s.game.Position.InCheck(BoardSideToColor(side)) {
+			return side, true
+		}
+	}
+	return BoardSideWhite, false
+}
+
+func (s *ChessScene) GetGame() *chess.Game {
+	return s.game
+}
+
+func (s *ChessScene) GetClock() *chess.Clock {
+	return s.clock
+}
+
+func (s *ChessScene) GetPosition() *chess.Position {
+	return s.game.Position
+}
+
+// every move made on the board goes through here so the history and result stay up to date
+func (s *ChessScene) CommitMove(move chess.Move) error {
+	event := MoveEvent{
+		Move:     move,
+		Piece:    s.game.Position.PieceAt(move.From),
+		Captured: s.game.Position.CapturedBy(move),
+	}
+	if err := s.game.Play(move); err != nil {
+		return err
+	}
+	s.clock.Press(event.Piece.Color)
+	s.SetOutcome(s.game.Outcome())
+
+	event.Check = s.game.Position.InCheck(event.Piece.Color.Other())
+	for _, listener := range s.moveListeners {
+		if err := listener(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ChessScene) AddMoveListener(listener MoveListener) {
+	s.moveListeners = append(s.moveListeners, listener)
+}
+
+func (s *ChessScene) GetOutcome() chess.Outcome {
+	return s.outcome
+}
+
+// also used for results decided off the board. the first result stands, anything after the game's over is ignored
+func (s *ChessScene) SetOutcome(outcome chess.Outcome) {
+	if s.outcome.IsOver() {
+		return
+	}
+	s.outcome = outcome
+	if !outcome.IsOver() {
+		return
+	}
+	s.clock.Stop()
+	log.Printf("Game over after %d moves: %s (%s)", len(s.game.Moves), outcome, outcome.Result)
+	log.Printf("Final position: %s", notation.FormatFEN(s.game.Position))
+	// a game that can't be saved is still worth finishing, so this only gets logged
+	if s.settings.PGNDir != "" {
+		filename, err := s.SavePGN(s.settings.PGNDir)
+		if err != nil {
+			log.Printf("Couldn't save game: %s", err)
+		} else {
+			log.Printf("Game saved to %s", filename)
+		}
+	}
+}
+
+func (s *ChessScene) GetHits(side BoardSide) int {
+	return s.hits[side]
+}
+
+// a side got shot, whatever the settings say happens on the board happens now
+func (s *ChessScene) RecordHit(side BoardSide) error {
+	if s.outcome.IsOver() {
+		return nil
+	}
+	s.hits[side]++
+	color := BoardSideToColor(side)
+
+	switch s.settings.HitPenalty {
+	case HitPenaltyClockTime:
+		if s.clock.Deduct(color, s.settings.HitClockPenalty) {
+			s.SetOutcome(s.game.Position.TimeoutOutcome(color))
+		}
+	case HitPenaltyForfeitTurn:
+		// can't skip out of check, that would let the king get taken
+		position := s.game.Position
+		if s.settings.Mode == GameModeTurns && position.SideToMove == color && !position.InCheck(color) {
+			s.game.Pass()
+			s.clock.Press(color)
+			s.ClearSelection()
+			s.SetOutcome(s.game.Outcome())
+		}
+	case HitPenaltyLosePiece:
+		return s.removeRandomPiece(side)
+	}
+	return nil
+}
+
+// never the king, and never a piece that's keeping its own king out of check (pinned), since the other side
+// could take the king then. if it's the side's own move, never one blocking a check on the other king either,
+// or it could take that king straight away. if there's nothing that can go, nothing does
+func (s *ChessScene) removeRandomPiece(side BoardSide) error {
+	color := BoardSideToColor(side)
+	toMove := s.game.Position.SideToMove == color
+	candidates := make([]chess.Square, 0)
+	for sq := chess.Square(0); sq < 64; sq++ {
+		piece := s.game.Position.PieceAt(sq)
+		if piece.IsEmpty() || piece.Color != color || piece.Type == chess.King {
+			continue
+		}
+		without := s.game.Position.Copy()
+		without.SetPiece(sq, chess.NoPiece)
+		if without.InCheck(color) || toMove && without.InCheck(color.Other()) {
+			continue
+		}
+		candidates = append(candidates, sq)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	square := candidates[rand.Intn(len(candidates))]
+	actor, err := FindPieceActorAt(s, SquareToNative(square))
+	if err != nil {
+		return err
+	}
+	if err := s.RemoveActor(actor.GetId()); err != nil {
+		return err
+	}
+	s.game.RemovePiece(square)
+	s.SetOutcome(s.game.Outcome())
+	return nil
+}
+
+func (s *ChessScene) GetSelection() (BoardSquare, bool) {
+	return s.selection, s.hasSelection
+}
+
+func (s *ChessScene) ClearSelection() {
+	s.hasSelection = false
+}
+
+// click input: the first click picks a piece, the next one moves it there or picks something else
+func (s *ChessScene) ClickSquare(square BoardSquare) error {
+	if s.hasSelection {
+		s.hasSelection = false
+		if s.selection == square {
+			return nil
+		}
+		selectedActor, err := FindPieceActorAt(s, s.selection)
+		if err != nil {
+			return err
+		}
+		selectedComp, err := GetChessPiece(selectedActor)
+		if err != nil {
+			return err
+		}
+		moved, err := selectedComp.SetPosition(square)
+		if err != nil || moved {
+			return err
+		}
+	}
+
+	target, found, err := PieceActorAt(s, square)
+	if err != nil || !found {
+		return err
+	}
+	targetComp, err := GetChessPiece(target)
+	if err != nil {
+		return err
+	}
+	canMove, err := targetComp.CanMove()
+	if err != nil {
+		return err
+	}
+	if canMove {
+		s.selection = square
+		s.hasSelection = true
+	}
+	return nil
+}
+
+func (s *ChessScene) Update(sim SimClockInterface) error {
+	if err := s.Scene.Update(sim); err != nil {
+		return err
+	}
+	if !s.outcome.IsOver() && s.clock.Tick(sim.GetDT()) {
+		flagged, _ := s.clock.Flagged()
+		s.SetOutcome(s.game.Position.TimeoutOutcome(flagged))
+	}
+	if s.outcome.IsOver() {
+		s.gameOverTicks++
+		if s.gameOverTicks >= GameOverDelayTicks {
+			s.SetNextScene(ResultsSceneId)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+	"github.com/val-is/bullet-hell-chess/notation"
+)
+
+// turn based and untimed, and nothing gets saved when it ends
+func newTestChessScene(t *testing.T, fen string) *ChessScene {
+	position, err := notation.ParseFEN(fen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings := DefaultGameSettings()
+	settings.Mode = GameModeTurns
+	settings.TimeControl = chess.Untimed
+	settings.PGNDir = ""
+	scene, err := NewChessScene(position, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return scene.(*ChessScene)
+}
+
+// a piece actor without sprites, enough for the rules side of things
+func newBarePiece(t *testing.T, scene SceneInterface, side BoardSide, pieceType ChessPiece, square string) *ComponentChessPiece {
+	position, err := ParseBoardSquare(square)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actor := &Actor{parentScene: scene, actorType: ActorTypeChessPiece, id: NewId("piece-" + square)}
+	piece := &ComponentChessPiece{
+		Component: Component{actor, ComponentTypeChessPiece},
+		color:     side,
+		pieceType: pieceType,
+		position:  position,
+	}
+	actor.components = []ComponentInterface{piece}
+	return piece
+}
+
+func TestRemoveRandomPiece(t *testing.T) {
+	// the knight on e4 is all that stands between the rook and black's king
+	const fen = "4k3/8/8/8/4N3/8/8/K3R3 w - - 0 1"
+	for i := 0; i < 50; i++ {
+		scene := newTestChessScene(t, fen)
+		for _, p := range []struct {
+			side      BoardSide
+			pieceType ChessPiece
+			square    string
+		}{
+			{BoardSideWhite, PieceKing, "a1"},
+			{BoardSideWhite, PieceRook, "e1"},
+			{BoardSideWhite, PieceKnight, "e4"},
+			{BoardSideBlack, PieceKing, "e8"},
+		} {
+			piece := newBarePiece(t, scene, p.side, p.pieceType, p.square)
+			if err := scene.AddActor(piece.parentActor); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := scene.removeRandomPiece(BoardSideWhite); err != nil {
+			t.Fatal(err)
+		}
+		position := scene.GetPosition()
+		if e4, _ := notation.ParseSquare("e4"); position.PieceAt(e4).IsEmpty() {
+			t.Fatalf("knight was taken off with white to move, leaving black's king en prise: %s",
+				notation.FormatFEN(position))
+		}
+		if e1, _ := notation.ParseSquare("e1"); !position.PieceAt(e1).IsEmpty() {
+			t.Fatalf("the rook should have been the only piece that could go: %s", notation.FormatFEN(position))
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+)
+
+// keeps a text sprite showing one side's remaining time and lives
+const ComponentTypeClockDisplay = "component-clock-display"
+
+type ComponentClockDisplay struct {
+	Component
+	side BoardSide
+	text TextSpriteInterface
+}
+
+type ComponentClockDisplayInterface interface {
+	ComponentInterface
+}
+
+func NewComponentClockDisplay(parent ActorInterface, side BoardSide, text TextSpriteInterface) (ComponentClockDisplayInterface, error) {
+	return &ComponentClockDisplay{
+		Component: Component{parent, ComponentTypeClockDisplay},
+		side:      side,
+		text:      text,
+	}, nil
+}
+
+func (c *ComponentClockDisplay) Update(sim SimClockInterface) error {
+	scene, ok := c.parentActor.GetParentScene().(ChessSceneInterface)
+	if !ok {
+		return fmt.Errorf("clock %s is not part of a chess scene", c.parentActor.GetId())
+	}
+	clock := scene.GetClock()
+	color := BoardSideToColor(c.side)
+	text := string(c.side)
+	if clock.Control.IsTimed() {
+		marker := " "
+		if clock.Running() == color && !scene.GetOutcome().IsOver() {
+			marker = ">"
+		}
+		text = fmt.Sprintf("%s %s  %s", marker, c.side, FormatClockTime(clock.Remaining(color)))
+	}
+	if scene.GetSettings().Lives > 0 {
+		cursor, err := scene.GetActorId(PlayerCursorId(c.side))
+		if err != nil {
+			return err
+		}
+		health, err := GetHealth(cursor)
+		if err != nil {
+			return err
+		}
+		text += fmt.Sprintf("  lives %d", health.GetLives())
+	}
+	c.text.SetText(text)
+	return nil
+}
+
+// m:ss, with tenths once it's under ten seconds
+func FormatClockTime(d time.Duration) string {
+	if d < 10*time.Second {
+		return fmt.Sprintf("0:%04.1f", d.Seconds())
+	}
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+const ActorTypeClock = "actor-clock"
+
+// black's clock goes above the board and white's below, matching which side of the board they play from
+func NewActorClock(parentScene SceneInterface, id string, side BoardSide) (ActorInterface, error) {
+	actor := Actor{
+		parentScene: parentScene,
+		actorType:   ActorTypeClock,
+		id:          id,
+		components:  make([]ComponentInterface, 0),
+	}
+
+	text, err := NewTextSprite("")
+	if err != nil {
+		return nil, err
+	}
+	textComp, err := NewComponentDrawable(&actor, text, RenderLayerUI)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, textComp)
+
+	y := (ScreenHeight-BoardHeight)/2 - TextLineHeight - 8
+	if side == BoardSideWhite {
+		y = (ScreenHeight+BoardHeight)/2 + TextLineHeight + 16
+	}
+	worldly, err := NewComponentWorldly(&actor, 0, y, ScreenWidth, TextLineHeight, 0)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, worldly)
+
+	display, err := NewComponentClockDisplay(&actor, side, text)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, display)
+
+	return &actor, nil
+}
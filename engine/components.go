@@ -11,7 +11,7 @@ type Component struct {
 }
 
 type ComponentInterface interface {
-	Update() error
+	Update(sim SimClockInterface) error
 	GetComponentType() string
 }
 
@@ -21,7 +21,7 @@ func NewComponent(parent ActorInterface, componentType string) (ComponentInterfa
 	}, nil
 }
 
-func (c *Component) Update() error {
+func (c *Component) Update(sim SimClockInterface) error {
 	return nil
 }
 
@@ -37,13 +37,19 @@ type ComponentWorldly struct {
 	x, y  float64
 	w, h  float64
 	angle float64
+	// where it was at the end of the last tick, drawing happens somewhere in between
+	prevX, prevY float64
 }
 
 type ComponentWorldlyInterface interface {
 	ComponentInterface
 
 	GetPosition() (x, y float64)
+	// jumps straight there, nothing is drawn in between
 	SetPosition(x, y float64)
+	// moves there over the course of the tick
+	MovePosition(x, y float64)
+	GetDrawPosition(alpha float64) (x, y float64)
 	GetScale() (w, h float64)
 	SetScale(w, h float64)
 	GetAngle() float64
@@ -55,9 +61,22 @@ func NewComponentWorldly(parent ActorInterface, x, y, w, h, angle float64) (Comp
 	return &ComponentWorldly{
 		Component{parent, ComponentTypeWorldly},
 		x, y, w, h, angle,
+		x, y,
 	}, nil
 }
 
+func GetWorldly(actor ActorInterface) (ComponentWorldlyInterface, error) {
+	component, err := actor.GetComponent(ComponentTypeWorldly)
+	if err != nil {
+		return nil, err
+	}
+	worldly, ok := component.(ComponentWorldlyInterface)
+	if !ok {
+		return nil, wrongComponentError(actor, component, "ComponentWorldlyInterface")
+	}
+	return worldly, nil
+}
+
 func (c *ComponentWorldly) GetPosition() (x, y float64) {
 	return c.x, c.y
 }
@@ -65,10 +84,23 @@ func (c *ComponentWorldly) GetPosition() (x, y float64) {
 func (c *ComponentWorldly) SetPosition(x, y float64) {
 	c.x = x
 	c.y = y
+	c.prevX = x
+	c.prevY = y
+}
+
+func (c *ComponentWorldly) MovePosition(x, y float64) {
+	c.prevX = c.x
+	c.prevY = c.y
+	c.x = x
+	c.y = y
+}
+
+func (c *ComponentWorldly) GetDrawPosition(alpha float64) (x, y float64) {
+	return Lerp(c.prevX, c.x, alpha), Lerp(c.prevY, c.y, alpha)
 }
 
 func (c *ComponentWorldly) GetScale() (w, h float64) {
-	return c.x, c.y
+	return c.w, c.h
 }
 
 func (c *ComponentWorldly) SetScale(w, h float64) {
@@ -0,0 +1,142 @@
+package engine
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"github.com/val-is/bullet-hell-chess/ecs"
+	"github.com/val-is/bullet-hell-chess/pattern"
+)
+
+// component that fires a pattern on a timer, and takes its actor away once it's done
+const ComponentTypeEmitter = "component-emitter"
+
+type ComponentEmitter struct {
+	Component
+	pattern pattern.Emitter
+	ticks   int
+	shots   int
+	// standalone emitters take their actor with them when they finish, ones riding on bullets don't
+	ownsActor bool
+	// kept between shots so firing doesn't allocate
+	spawns []pattern.Spawn
+}
+
+type ComponentEmitterInterface interface {
+	ComponentInterface
+	ResettableInterface
+	GetPattern() pattern.Emitter
+	// swaps the pattern and starts it over, the pattern isn't validated again
+	SetPattern(p pattern.Emitter)
+	Fire() error
+	IsDone() bool
+}
+
+func NewComponentEmitter(parent ActorInterface, p pattern.Emitter, ownsActor bool) (ComponentEmitterInterface, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &ComponentEmitter{
+		Component: Component{parent, ComponentTypeEmitter},
+		pattern:   p,
+		ownsActor: ownsActor,
+	}, nil
+}
+
+func (c *ComponentEmitter) GetPattern() pattern.Emitter {
+	return c.pattern
+}
+
+func (c *ComponentEmitter) SetPattern(p pattern.Emitter) {
+	c.pattern = p
+	c.Reset()
+}
+
+func (c *ComponentEmitter) Reset() {
+	c.ticks = 0
+	c.shots = 0
+}
+
+// spawns one shot's worth of bullets from the middle of the emitter's actor
+// plain bullets go into the scene's bullet field if there is one, ones with children are always actors
+func (c *ComponentEmitter) Fire() error {
+	worldly, err := GetWorldly(c.parentActor)
+	if err != nil {
+		return err
+	}
+	bbx, bby, bbw, bbh := worldly.GetBoundingBox()
+	x, y := bbx+bbw/2, bby+bbh/2
+	mx, my := ebiten.CursorPosition()
+	scene := c.parentActor.GetParentScene()
+	field, hasField, err := FindBulletField(scene)
+	if err != nil {
+		return err
+	}
+	c.spawns = c.pattern.AppendSpawns(c.spawns[:0], c.shots, x, y, float64(mx), float64(my))
+	for _, spawn := range c.spawns {
+		if hasField && len(c.pattern.Children) == 0 {
+			field.Spawn(ecs.Body{
+				Position:     ecs.Vec2{X: spawn.X, Y: spawn.Y},
+				Velocity:     ecs.Vec2{X: spawn.VX, Y: spawn.VY},
+				Acceleration: ecs.Vec2{X: spawn.AX, Y: spawn.AY},
+				Radius:       c.pattern.Radius,
+				Lifetime:     c.pattern.Lifetime,
+			})
+			continue
+		}
+		bullet, err := NewActorBullet(scene, spawn.X, spawn.Y, c.pattern.Radius,
+			spawn.VX, spawn.VY, spawn.AX, spawn.AY, c.pattern.Lifetime, c.pattern.Children)
+		if err != nil {
+			return err
+		}
+		if err := scene.AddActor(bullet); err != nil {
+			return err
+		}
+	}
+	c.shots++
+	return nil
+}
+
+func (c *ComponentEmitter) IsDone() bool {
+	return c.pattern.Repeat > 0 && c.shots >= c.pattern.Repeat
+}
+
+func (c *ComponentEmitter) Update(sim SimClockInterface) error {
+	if c.IsDone() {
+		return nil
+	}
+	if since := c.ticks - c.pattern.Delay; since >= 0 && since%c.pattern.Interval == 0 {
+		if err := c.Fire(); err != nil {
+			return err
+		}
+	}
+	c.ticks++
+	if c.IsDone() && c.ownsActor {
+		return c.parentActor.GetParentScene().RemoveActor(c.parentActor.GetId())
+	}
+	return nil
+}
+
+const ActorTypeEmitter = "actor-emitter"
+
+// emitters are just a point, bullets come out of x, y
+func NewActorEmitter(parentScene SceneInterface, x, y float64, p pattern.Emitter) (ActorInterface, error) {
+	actor := Actor{
+		parentScene: parentScene,
+		actorType:   ActorTypeEmitter,
+		id:          NewId("emitter-"),
+		components:  make([]ComponentInterface, 0),
+	}
+
+	worldly, err := NewComponentWorldly(&actor, x, y, 0, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, worldly)
+
+	emitter, err := NewComponentEmitter(&actor, p, true)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, emitter)
+
+	return &actor, nil
+}
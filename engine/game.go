@@ -2,27 +2,52 @@ package engine
 
 import (
 	"os"
+	"time"
 
 	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+	"github.com/hajimehoshi/ebiten/inpututil"
 )
 
 type Game struct {
 	sceneManager SceneMachineInterface
+	sim          SimClockInterface
+	// when Update last ran, what the sim clock's given is the real time since
+	lastUpdate time.Time
 }
 
-func NewGameInstance() (ebiten.Game, error) {
+func NewGameInstance(settings GameSettings) (ebiten.Game, error) {
 	sceneMachine, err := NewSceneMachine()
 	if err != nil {
 		return nil, err
 	}
 
-	sceneMachine.AddScene(StartSceneId, NewMainScene)
-	if err := sceneMachine.RunScene(StartSceneId); err != nil {
+	sceneMachine.AddScene(StartSceneId, func() (SceneInterface, error) {
+		return NewMainScene(settings)
+	})
+	// the results generator runs before the machine switches over, so the current scene is the finished game
+	sceneMachine.AddScene(ResultsSceneId, func() (SceneInterface, error) {
+		return NewResultsScene(sceneMachine.GetCurrentScene())
+	})
+	sceneMachine.AddScene(ReplaySceneId, func() (SceneInterface, error) {
+		return NewReplayScene(settings.ReplayFile)
+	})
+	firstScene := StartSceneId
+	if settings.ReplayFile != "" {
+		firstScene = ReplaySceneId
+	}
+	if err := sceneMachine.RunScene(firstScene); err != nil {
+		return nil, err
+	}
+
+	sim, err := NewSimClock()
+	if err != nil {
 		return nil, err
 	}
 
 	g := Game{
 		sceneManager: sceneMachine,
+		sim:          sim,
 	}
 
 	ebiten.SetWindowSize(ScreenWidth, ScreenHeight)
@@ -35,13 +60,41 @@ func (g *Game) Update(screen *ebiten.Image) error {
 	if g.sceneManager.GetCurrentScene().GetId() == StopSceneId {
 		os.Exit(0)
 	}
-	return g.sceneManager.Update()
+	if inpututil.IsKeyJustPressed(ebiten.KeyP) {
+		g.sim.SetPaused(!g.sim.IsPaused())
+	}
+	// input's handled per frame however many ticks there are, nothing gets picked up while paused
+	if !g.sim.IsPaused() {
+		if err := g.sceneManager.UpdateInput(); err != nil {
+			return err
+		}
+	}
+	// ebiten aims to call this MaxTPS times a second, but a slow frame has to be caught up on
+	now := time.Now()
+	elapsed := time.Second / time.Duration(ebiten.MaxTPS())
+	if !g.lastUpdate.IsZero() {
+		elapsed = now.Sub(g.lastUpdate)
+	}
+	g.lastUpdate = now
+	steps := g.sim.Advance(elapsed, now)
+	for i := 0; i < steps; i++ {
+		g.sim.Step()
+		if err := g.sceneManager.Update(g.sim); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
+// can be called more or less often than Update, things are drawn part way between their last two ticks
 func (g *Game) Draw(screen *ebiten.Image) {
-	if err := g.sceneManager.Draw(screen); err != nil {
+	g.sim.Interpolate(time.Now())
+	if err := g.sceneManager.Draw(screen, g.sim); err != nil {
 		panic(err)
 	}
+	if g.sim.IsPaused() {
+		ebitenutil.DebugPrintAt(screen, "paused, p to resume", 4, 4)
+	}
 }
 
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
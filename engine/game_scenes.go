@@ -1,7 +1,17 @@
 package engine
 
-func NewMainScene() (SceneInterface, error) {
-	baseScene, err := NewScene()
+import (
+	"github.com/hajimehoshi/ebiten"
+	"github.com/val-is/bullet-hell-chess/notation"
+	"github.com/val-is/bullet-hell-chess/pattern"
+)
+
+func NewMainScene(settings GameSettings) (SceneInterface, error) {
+	position, err := notation.ParseFEN(settings.FEN)
+	if err != nil {
+		return nil, err
+	}
+	baseScene, err := NewChessScene(position, settings)
 	if err != nil {
 		return nil, err
 	}
@@ -10,59 +20,143 @@ func NewMainScene() (SceneInterface, error) {
 	if err != nil {
 		return nil, err
 	}
-	baseScene.AddActor(bgActor)
+	if err := baseScene.AddActor(bgActor); err != nil {
+		return nil, err
+	}
 
-	// set up standard board
-	pieceSpriteDir := "assets/sprites/chessboard/chess_green/"
+	// pieces go wherever the fen put them
+	if err := AddPieceActors(baseScene, position, PieceAssetDir); err != nil {
+		return nil, err
+	}
 
-	addPieces := func(pieceType ChessPiece, row int, columns ...int) error {
-		for _, col := range columns {
-			// white
-			piece, err := NewActorChessPiece(baseScene, BoardSideWhite, pieceType, AlgebraicToNative(col, row), pieceSpriteDir)
-			if err != nil {
-				return err
-			}
-			baseScene.AddActor(piece)
-			// black (mirrored)
-			piece, err = NewActorChessPiece(baseScene, BoardSideBlack, pieceType, AlgebraicToNative(col, 9-row), pieceSpriteDir)
-			if err != nil {
-				return err
+	testBoardActor, err := NewActorBoard(baseScene, "board-actor", "assets/sprites/chessboard/chess_green/board.png")
+	if err != nil {
+		return nil, err
+	}
+	if err := baseScene.AddActor(testBoardActor); err != nil {
+		return nil, err
+	}
+
+	// clicking off the board drops the selection in click input mode
+	if settings.InputMode == InputModeClick {
+		deselectArea, err := NewActorClickArea(baseScene, "deselect-area", 0, 0, ScreenWidth, ScreenHeight, func() error {
+			mx, my := ebiten.CursorPosition()
+			if _, onBoard := ScreenToBoardSquare(float64(mx), float64(my)); !onBoard {
+				baseScene.ClearSelection()
 			}
-			baseScene.AddActor(piece)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := baseScene.AddActor(deselectArea); err != nil {
+			return nil, err
+		}
+	}
+
+	// plain bullets all go in here, added before anything can fire so it's updated ahead of the emitters
+	bulletField, err := NewActorBulletField(baseScene)
+	if err != nil {
+		return nil, err
+	}
+	if err := baseScene.AddActor(bulletField); err != nil {
+		return nil, err
+	}
+
+	if settings.TriggerFile != "" {
+		patterns, err := pattern.LoadDir(PatternDir)
+		if err != nil {
+			return nil, err
+		}
+		triggers, err := LoadPatternTriggers(settings.TriggerFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := triggers.Validate(patterns); err != nil {
+			return nil, err
+		}
+		baseScene.AddMoveListener(NewPatternTriggerListener(baseScene, triggers, patterns))
+	}
+
+	turnIndicator, err := NewActorTurnIndicator(baseScene, "turn-indicator")
+	if err != nil {
+		return nil, err
+	}
+	if err := baseScene.AddActor(turnIndicator); err != nil {
+		return nil, err
+	}
+
+	for _, side := range []BoardSide{BoardSideWhite, BoardSideBlack} {
+		clock, err := NewActorClock(baseScene, "clock-"+string(side), side)
+		if err != nil {
+			return nil, err
 		}
-		return nil
+		if err := baseScene.AddActor(clock); err != nil {
+			return nil, err
+		}
+
+		cursor, err := NewActorPlayerCursor(baseScene, side, settings)
+		if err != nil {
+			return nil, err
+		}
+		if err := baseScene.AddActor(cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	return baseScene, nil
+}
+
+// shows how the last game ended, the previous scene is whatever the machine was running when we got here
+func NewResultsScene(previousScene SceneInterface) (SceneInterface, error) {
+	baseScene, err := NewScene()
+	if err != nil {
+		return nil, err
 	}
 
-	// pawns
-	if err := addPieces(PiecePawn, 2, 1, 2, 3, 4, 5, 6, 7, 8); err != nil {
+	bgActor, err := NewActorBackgroundImage(baseScene, "scene-background", "assets/sprites/chessboard/chess_green/bg.png")
+	if err != nil {
 		return nil, err
 	}
-	// rooks
-	if err := addPieces(PieceRook, 1, 1, 8); err != nil {
+	if err := baseScene.AddActor(bgActor); err != nil {
 		return nil, err
 	}
-	// knights
-	if err := addPieces(PieceKnight, 1, 2, 7); err != nil {
+
+	resultText := "game over"
+	if chessScene, ok := previousScene.(ChessSceneInterface); ok {
+		outcome := chessScene.GetOutcome()
+		resultText = outcome.Result.String() + "\n" + outcome.String()
+	}
+	text, err := NewTextSprite(resultText)
+	if err != nil {
 		return nil, err
 	}
-	// bishops
-	if err := addPieces(PieceBishop, 1, 3, 6); err != nil {
+	textActor, err := NewActorText(baseScene, "results-text", text, 0, ScreenHeight/2-100, RenderLayerUI)
+	if err != nil {
 		return nil, err
 	}
-	// queens
-	if err := addPieces(PieceQueen, 1, 4); err != nil {
+	// stretch across the screen so the text sits in the middle
+	worldly, err := GetWorldly(textActor)
+	if err != nil {
 		return nil, err
 	}
-	// kings
-	if err := addPieces(PieceKing, 1, 5); err != nil {
+	_, th := text.GetSize()
+	worldly.SetScale(ScreenWidth, th)
+	if err := baseScene.AddActor(textActor); err != nil {
 		return nil, err
 	}
 
-	testBoardActor, err := NewActorBoard(baseScene, "board-actor", "assets/sprites/chessboard/chess_green/board.png")
+	playAgain, err := NewActorButton(baseScene, "results-play-again", "play again",
+		(ScreenWidth-160)/2, ScreenHeight/2, 160, 40, func() error {
+			baseScene.SetNextScene(StartSceneId)
+			return nil
+		})
 	if err != nil {
 		return nil, err
 	}
-	baseScene.AddActor(testBoardActor)
+	if err := baseScene.AddActor(playAgain); err != nil {
+		return nil, err
+	}
 
 	return baseScene, nil
 }
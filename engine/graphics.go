@@ -2,7 +2,9 @@ package engine
 
 import (
 	"image"
+	"image/color"
 	"math"
+	"strings"
 
 	"github.com/hajimehoshi/ebiten"
 	"github.com/hajimehoshi/ebiten/ebitenutil"
@@ -20,6 +22,7 @@ const (
 	RenderLayerUI               RenderLayer = iota
 	RenderLayerForeground       RenderLayer = iota
 	RenderLayerForegroundObject RenderLayer = iota
+	RenderLayerBullet           RenderLayer = iota
 )
 
 func LoadImageFromFile(filename string) (*ebiten.Image, error) {
@@ -64,6 +67,75 @@ func NewBasicSpriteFromPath(filename string) (SpriteInterface, error) {
 	return &s, nil
 }
 
+// solid block of colour, for backdrops and anything else that doesn't need an asset
+func NewColorSprite(w, h int, clr color.Color) (SpriteInterface, error) {
+	img, err := ebiten.NewImage(w, h, ebiten.FilterDefault)
+	if err != nil {
+		return nil, err
+	}
+	if err := img.Fill(clr); err != nil {
+		return nil, err
+	}
+	return &BasicSprite{img, float64(w), float64(h)}, nil
+}
+
+// filled circle, drawn into a square image of side 2*radius
+func NewCircleSprite(radius int, clr color.Color) (SpriteInterface, error) {
+	size := 2 * radius
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	r := float64(radius)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dx, dy := float64(x)+0.5-r, float64(y)+0.5-r
+			if dx*dx+dy*dy <= r*r {
+				img.Set(x, y, clr)
+			}
+		}
+	}
+	ebitenImage, err := ebiten.NewImageFromImage(img, ebiten.FilterDefault)
+	if err != nil {
+		return nil, err
+	}
+	return &BasicSprite{ebitenImage, float64(size), float64(size)}, nil
+}
+
+// sprites are only read from when drawing, so every actor showing the same image can share one
+var (
+	pathSprites   = make(map[string]SpriteInterface)
+	circleSprites = make(map[circleSpriteKey]SpriteInterface)
+)
+
+type circleSpriteKey struct {
+	radius int
+	clr    color.RGBA
+}
+
+// loads the file the first time, after that it's the same sprite every time
+func GetSpriteFromPath(filename string) (SpriteInterface, error) {
+	if sprite, ok := pathSprites[filename]; ok {
+		return sprite, nil
+	}
+	sprite, err := NewBasicSpriteFromPath(filename)
+	if err != nil {
+		return nil, err
+	}
+	pathSprites[filename] = sprite
+	return sprite, nil
+}
+
+func GetCircleSprite(radius int, clr color.RGBA) (SpriteInterface, error) {
+	key := circleSpriteKey{radius, clr}
+	if sprite, ok := circleSprites[key]; ok {
+		return sprite, nil
+	}
+	sprite, err := NewCircleSprite(radius, clr)
+	if err != nil {
+		return nil, err
+	}
+	circleSprites[key] = sprite
+	return sprite, nil
+}
+
 func (s *BasicSprite) Draw(screen *ebiten.Image, x, y, w, h, angle float64) error {
 	drawOptions := ebiten.DrawImageOptions{}
 	drawOptions.GeoM.Reset()
@@ -77,6 +149,58 @@ func (s *BasicSprite) GetSize() (float64, float64) {
 	return s.w, s.h
 }
 
+// sprite that draws text with ebiten's debug font, the text can be swapped out any time
+// text is centered if there's room for it, but never scaled or rotated
+const (
+	TextCharWidth  = 6.0
+	TextLineHeight = 16.0
+)
+
+type TextSprite struct {
+	text string
+}
+
+type TextSpriteInterface interface {
+	SpriteInterface
+	GetText() string
+	SetText(text string)
+}
+
+func NewTextSprite(text string) (TextSpriteInterface, error) {
+	return &TextSprite{text}, nil
+}
+
+func (s *TextSprite) Draw(screen *ebiten.Image, x, y, w, h, angle float64) error {
+	tw, th := s.GetSize()
+	if w > tw {
+		x += (w - tw) / 2
+	}
+	if h > th {
+		y += (h - th) / 2
+	}
+	ebitenutil.DebugPrintAt(screen, s.text, int(x), int(y))
+	return nil
+}
+
+func (s *TextSprite) GetSize() (float64, float64) {
+	lines := strings.Split(s.text, "\n")
+	longest := 0
+	for _, line := range lines {
+		if len(line) > longest {
+			longest = len(line)
+		}
+	}
+	return TextCharWidth * float64(longest), TextLineHeight * float64(len(lines))
+}
+
+func (s *TextSprite) GetText() string {
+	return s.text
+}
+
+func (s *TextSprite) SetText(text string) {
+	s.text = text
+}
+
 // generic component that's drawable
 const ComponentTypeDrawable = "component-drawable"
 
@@ -89,8 +213,10 @@ type ComponentDrawable struct {
 
 type ComponentDrawableInterface interface {
 	ComponentInterface
-	Draw(screen *ebiten.Image, renderLayer RenderLayer) error
+	Draw(screen *ebiten.Image, renderLayer RenderLayer, sim SimClockInterface) error
 	GetRenderLayer() RenderLayer
+	SetRenderLayer(renderLayer RenderLayer)
+	SetSprite(sprite SpriteInterface)
 	CheckIfDrawable(renderLayer RenderLayer) bool
 	GetActive() bool
 	SetActive(active bool)
@@ -108,22 +234,58 @@ func NewComponentDrawable(parent ActorInterface, sprite SpriteInterface, renderL
 	return &component, nil
 }
 
-func (c *ComponentDrawable) Draw(screen *ebiten.Image, renderLayer RenderLayer) error {
+// the first drawable on the actor
+func GetDrawable(actor ActorInterface) (ComponentDrawableInterface, error) {
+	component, err := actor.GetComponent(ComponentTypeDrawable)
+	if err != nil {
+		return nil, err
+	}
+	drawable, ok := component.(ComponentDrawableInterface)
+	if !ok {
+		return nil, wrongComponentError(actor, component, "ComponentDrawableInterface")
+	}
+	return drawable, nil
+}
+
+// every drawable on the actor, in the order they're drawn
+func GetDrawables(actor ActorInterface) ([]ComponentDrawableInterface, error) {
+	components := actor.GetComponents(ComponentTypeDrawable)
+	drawables := make([]ComponentDrawableInterface, 0, len(components))
+	for _, component := range components {
+		drawable, ok := component.(ComponentDrawableInterface)
+		if !ok {
+			return nil, wrongComponentError(actor, component, "ComponentDrawableInterface")
+		}
+		drawables = append(drawables, drawable)
+	}
+	return drawables, nil
+}
+
+func (c *ComponentDrawable) Draw(screen *ebiten.Image, renderLayer RenderLayer, sim SimClockInterface) error {
 	if !c.CheckIfDrawable(renderLayer) {
 		return nil
 	}
-	wComp, err := c.parentActor.GetComponent(ComponentTypeWorldly)
+	worldly, err := GetWorldly(c.parentActor)
 	if err != nil {
 		return err
 	}
-	w := wComp.(*ComponentWorldly)
-	return c.sprite.Draw(screen, w.x, w.y, w.w, w.h, w.angle)
+	x, y := worldly.GetDrawPosition(sim.GetAlpha())
+	w, h := worldly.GetScale()
+	return c.sprite.Draw(screen, x, y, w, h, worldly.GetAngle())
 }
 
 func (c *ComponentDrawable) GetRenderLayer() RenderLayer {
 	return c.renderLayer
 }
 
+func (c *ComponentDrawable) SetRenderLayer(renderLayer RenderLayer) {
+	c.renderLayer = renderLayer
+}
+
+func (c *ComponentDrawable) SetSprite(sprite SpriteInterface) {
+	c.sprite = sprite
+}
+
 func (c *ComponentDrawable) CheckIfDrawable(renderLayer RenderLayer) bool {
 	if !c.GetActive() {
 		return false
@@ -152,7 +314,7 @@ func NewActorBackgroundImage(parentScene SceneInterface, id, filename string) (A
 		components:  make([]ComponentInterface, 0),
 	}
 
-	sprite, err := NewBasicSpriteFromPath(filename)
+	sprite, err := GetSpriteFromPath(filename)
 	if err != nil {
 		return nil, err
 	}
@@ -170,3 +332,29 @@ func NewActorBackgroundImage(parentScene SceneInterface, id, filename string) (A
 
 	return &actor, nil
 }
+
+const ActorTypeText = "actor-text"
+
+func NewActorText(parentScene SceneInterface, id string, text TextSpriteInterface, x, y float64, renderLayer RenderLayer) (ActorInterface, error) {
+	actor := Actor{
+		parentScene: parentScene,
+		actorType:   ActorTypeText,
+		id:          id,
+		components:  make([]ComponentInterface, 0),
+	}
+
+	textComp, err := NewComponentDrawable(&actor, text, renderLayer)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, textComp)
+
+	w, h := text.GetSize()
+	worldly, err := NewComponentWorldly(&actor, x, y, w, h, 0)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, worldly)
+
+	return &actor, nil
+}
@@ -54,6 +54,18 @@ func NewComponentClickable(parent ActorInterface) (ComponentClickableInterface,
 	}, nil
 }
 
+func GetClickable(actor ActorInterface) (ComponentClickableInterface, error) {
+	component, err := actor.GetComponent(ComponentTypeClickable)
+	if err != nil {
+		return nil, err
+	}
+	clickable, ok := component.(ComponentClickableInterface)
+	if !ok {
+		return nil, wrongComponentError(actor, component, "ComponentClickableInterface")
+	}
+	return clickable, nil
+}
+
 func (c *ComponentClickable) AddStateListener(state MouseState, listener ClickListener) {
 	c.mouseStateListeners[state] = append(c.mouseStateListeners[state], listener)
 }
@@ -92,11 +104,11 @@ func (c *ComponentClickable) UpdateMousePos(x, y int) error {
 }
 
 func (c *ComponentClickable) CheckMouseHover(x, y int) (bool, error) {
-	worldly, err := c.parentActor.GetComponent(ComponentTypeWorldly)
+	worldly, err := GetWorldly(c.parentActor)
 	if err != nil {
 		return false, err
 	}
-	bbx, bby, bbw, bbh := worldly.(*ComponentWorldly).GetBoundingBox()
+	bbx, bby, bbw, bbh := worldly.GetBoundingBox()
 	return CheckBoundingBox(bbx, bby, bbw, bbh, float64(x), float64(y)), nil
 }
 
@@ -108,7 +120,8 @@ func (c *ComponentClickable) GetMouseHover() bool {
 	return c.mouseHover
 }
 
-func (c *ComponentClickable) Update() error {
+// runs once a frame rather than once a tick, see UpdateInputInterface
+func (c *ComponentClickable) UpdateInput() error {
 	mx, my := ebiten.CursorPosition()
 	if err := c.UpdateMousePos(mx, my); err != nil {
 		return err
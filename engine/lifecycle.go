@@ -0,0 +1,86 @@
+package engine
+
+// optional hooks for actors and components, implement whichever are needed
+// actors pass each one on to any of their components that implement it
+
+// called once the actor is in the scene, lookups will find it
+type OnAddInterface interface {
+	OnAdd() error
+}
+
+// called as soon as the actor is removed, it's already gone from lookups
+type OnRemoveInterface interface {
+	OnRemove() error
+}
+
+// called for everything in a scene when the machine switches to it
+// actors added later on only get OnAdd
+type OnSceneEnterInterface interface {
+	OnSceneEnter() error
+}
+
+// called for everything still in a scene when the machine switches away from it
+type OnSceneExitInterface interface {
+	OnSceneExit() error
+}
+
+// called once a frame before any ticks are stepped, for anything reading just pressed/released input
+// ebiten only reports those for the one frame, so a tick can see them twice or not at all
+type UpdateInputInterface interface {
+	UpdateInput() error
+}
+
+func (a *Actor) OnAdd() error {
+	for k := range a.components {
+		if hook, ok := a.components[k].(OnAddInterface); ok {
+			if err := hook.OnAdd(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (a *Actor) OnRemove() error {
+	for k := range a.components {
+		if hook, ok := a.components[k].(OnRemoveInterface); ok {
+			if err := hook.OnRemove(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (a *Actor) UpdateInput() error {
+	for k := range a.components {
+		if hook, ok := a.components[k].(UpdateInputInterface); ok {
+			if err := hook.UpdateInput(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (a *Actor) OnSceneEnter() error {
+	for k := range a.components {
+		if hook, ok := a.components[k].(OnSceneEnterInterface); ok {
+			if err := hook.OnSceneEnter(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (a *Actor) OnSceneExit() error {
+	for k := range a.components {
+		if hook, ok := a.components[k].(OnSceneExitInterface); ok {
+			if err := hook.OnSceneExit(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package engine
+
+import "github.com/val-is/bullet-hell-chess/pattern"
+
+const PatternDir = "assets/patterns"
+
+// starts every emitter in the pattern at x, y
+func SpawnPattern(parentScene SceneInterface, file pattern.File, x, y float64) error {
+	for _, emitterPattern := range file.Emitters {
+		emitter, err := NewActorEmitter(parentScene, x, y, emitterPattern)
+		if err != nil {
+			return err
+		}
+		if err := parentScene.AddActor(emitter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,7 +1,10 @@
 package engine
 
 import (
+	"fmt"
+
 	"github.com/hajimehoshi/ebiten"
+	"github.com/val-is/bullet-hell-chess/chess"
 )
 
 const (
@@ -38,6 +41,43 @@ const (
 
 type BoardSquare [2]int
 
+// conversions to and from the rules core
+func BoardSideToColor(side BoardSide) chess.Color {
+	if side == BoardSideBlack {
+		return chess.Black
+	}
+	return chess.White
+}
+
+func ColorToBoardSide(color chess.Color) BoardSide {
+	if color == chess.Black {
+		return BoardSideBlack
+	}
+	return BoardSideWhite
+}
+
+func ChessPieceToType(piece ChessPiece) chess.PieceType {
+	switch piece {
+	case PiecePawn:
+		return chess.Pawn
+	case PieceRook:
+		return chess.Rook
+	case PieceKnight:
+		return chess.Knight
+	case PieceBishop:
+		return chess.Bishop
+	case PieceQueen:
+		return chess.Queen
+	case PieceKing:
+		return chess.King
+	}
+	return chess.NoPieceType
+}
+
+func TypeToChessPiece(pieceType chess.PieceType) ChessPiece {
+	return ChessPiece(pieceType.String())
+}
+
 // component defining chess piece characteristics
 const ComponentTypeChessPiece = "component-chess-piece"
 
@@ -46,6 +86,12 @@ type ComponentChessPiece struct {
 	color     BoardSide
 	pieceType ChessPiece
 	position  BoardSquare
+	assetDir  string
+	// ticks left before the piece can move again in real-time games
+	cooldown int
+	dragging bool
+	// the promotion picker is open for this pawn
+	promoting bool
 }
 
 type ComponentChessPieceInterface interface {
@@ -53,15 +99,23 @@ type ComponentChessPieceInterface interface {
 	GetColor() BoardSide
 	GetPieceType() ChessPiece
 	GetPosition() BoardSquare
+	GetCooldown() int
+	CanMove() (bool, error)
 
-	SetPosition(square BoardSquare) bool
-	GetAvailableMoves() []BoardSquare
+	PickUp() (bool, error)
+	Drop(x, y float64) (bool, error)
+	IsDragging() bool
+
+	SetPosition(square BoardSquare) (bool, error)
+	PlaceAt(square BoardSquare)
+	Promote(square BoardSquare, pieceType ChessPiece) error
+	GetAvailableMoves() ([]BoardSquare, error)
 
 	LockToGrid() error
 }
 
 func NewComponentChessPiece(parent ActorInterface, color BoardSide,
-	pieceType ChessPiece, position BoardSquare) (ComponentChessPieceInterface, error) {
+	pieceType ChessPiece, position BoardSquare, assetDir string) (ComponentChessPieceInterface, error) {
 
 	component := ComponentChessPiece{
 		Component: Component{
@@ -70,11 +124,24 @@ func NewComponentChessPiece(parent ActorInterface, color BoardSide,
 		color:     color,
 		pieceType: pieceType,
 		position:  position,
+		assetDir:  assetDir,
 	}
 
 	return &component, nil
 }
 
+func GetChessPiece(actor ActorInterface) (ComponentChessPieceInterface, error) {
+	component, err := actor.GetComponent(ComponentTypeChessPiece)
+	if err != nil {
+		return nil, err
+	}
+	chessPiece, ok := component.(ComponentChessPieceInterface)
+	if !ok {
+		return nil, wrongComponentError(actor, component, "ComponentChessPieceInterface")
+	}
+	return chessPiece, nil
+}
+
 func (c *ComponentChessPiece) GetColor() BoardSide {
 	return c.color
 }
@@ -87,135 +154,269 @@ func (c *ComponentChessPiece) GetPosition() BoardSquare {
 	return c.position
 }
 
-func (c *ComponentChessPiece) SetPosition(square BoardSquare) bool {
-	// TODO replace with check for valid move
-	validSpaces := c.GetAvailableMoves()
-	movePresent := false
-	for _, space := range validSpaces {
-		if space[0] == square[0] && space[1] == square[1] {
-			movePresent = true
-			break
+// the rules live in the scene's position, pieces just look up their own square in it
+func (c *ComponentChessPiece) getChessScene() (ChessSceneInterface, error) {
+	scene, ok := c.parentActor.GetParentScene().(ChessSceneInterface)
+	if !ok {
+		return nil, fmt.Errorf("piece %s is not part of a chess scene", c.parentActor.GetId())
+	}
+	return scene, nil
+}
+
+func (c *ComponentChessPiece) getGamePosition() (*chess.Position, error) {
+	scene, err := c.getChessScene()
+	if err != nil {
+		return nil, err
+	}
+	return scene.GetPosition(), nil
+}
+
+func (c *ComponentChessPiece) GetCooldown() int {
+	return c.cooldown
+}
+
+// nothing can be picked up while a promotion is waiting on the player
+func (c *ComponentChessPiece) CanMove() (bool, error) {
+	scene, err := c.getChessScene()
+	if err != nil {
+		return false, err
+	}
+	if len(scene.GetActorsType(ActorTypePromotionOption)) > 0 {
+		return false, nil
+	}
+	return scene.IsTurn(c.color) && c.cooldown == 0, nil
+}
+
+// starts following the cursor, if the piece is allowed to move at all
+func (c *ComponentChessPiece) PickUp() (bool, error) {
+	canMove, err := c.CanMove()
+	if err != nil || !canMove {
+		return false, err
+	}
+	if err := c.setDrawLayer(RenderLayerUI); err != nil {
+		return false, err
+	}
+	c.dragging = true
+	return true, nil
+}
+
+// lets go of the piece over a point on screen, commits the move if it's legal and snaps back otherwise
+func (c *ComponentChessPiece) Drop(x, y float64) (bool, error) {
+	if !c.dragging {
+		return false, nil
+	}
+	c.dragging = false
+	if err := c.setDrawLayer(RenderLayerForegroundObject); err != nil {
+		return false, err
+	}
+	moved := false
+	if square, ok := ScreenToBoardSquare(x, y); ok {
+		var err error
+		if moved, err = c.SetPosition(square); err != nil {
+			return false, err
 		}
 	}
-	if !movePresent {
-		return false
+	return moved, c.LockToGrid()
+}
+
+func (c *ComponentChessPiece) IsDragging() bool {
+	return c.dragging
+}
+
+// held pieces are drawn over everything else on the board
+func (c *ComponentChessPiece) setDrawLayer(renderLayer RenderLayer) error {
+	spriteComp, err := GetDrawable(c.parentActor)
+	if err != nil {
+		return err
+	}
+	spriteComp.SetRenderLayer(renderLayer)
+	return nil
+}
+
+// promotions aren't committed here, the player gets a picker and the move finishes in Promote
+func (c *ComponentChessPiece) SetPosition(square BoardSquare) (bool, error) {
+	if canMove, err := c.CanMove(); err != nil || !canMove {
+		return false, err
 	}
-	// TODO handle piece-piece interactions
+	position, err := c.getGamePosition()
+	if err != nil {
+		return false, err
+	}
+	from, to := NativeToSquare(c.position), NativeToSquare(square)
+	if position.IsPromotion(from, to) {
+		if err := OpenPromotionPicker(c.parentActor.GetParentScene(), c.parentActor, c.color, square, c.assetDir); err != nil {
+			return false, err
+		}
+		c.promoting = true
+		return true, nil
+	}
+	move := chess.Move{From: from, To: to}
+	if !position.IsLegal(move) {
+		return false, nil
+	}
+	if err := c.commitMove(move); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// moves the piece without going through the rules, for pieces carried along by another piece's move
+func (c *ComponentChessPiece) PlaceAt(square BoardSquare) {
 	c.position = square
-	return true
 }
 
-func (c *ComponentChessPiece) GetAvailableMoves() []BoardSquare {
-	// i hate this _so_ inexplicably much but i guess it works
-	availMoves := make([]BoardSquare, 0)
-	pos := c.position
-	switch c.pieceType {
-	case PiecePawn:
-		if c.color == BoardSideBlack {
-			availMoves = append(availMoves,
-				BoardSquare{pos[0], pos[1] + 1},
-				BoardSquare{pos[0], pos[1] + 2},
-				BoardSquare{pos[0] - 1, pos[1] + 1},
-				BoardSquare{pos[0] + 1, pos[1] + 1},
-			)
-		} else if c.color == BoardSideWhite {
-			availMoves = append(availMoves,
-				BoardSquare{pos[0], pos[1] - 1},
-				BoardSquare{pos[0], pos[1] - 2},
-				BoardSquare{pos[0] - 1, pos[1] - 1},
-				BoardSquare{pos[0] + 1, pos[1] - 1},
-			)
+// the picker stays up while the clock runs and bullets fly, so the game can be over or the turn lost by the
+// time a piece is chosen. the pawn just stays where it was then, and the picker closes as usual
+func (c *ComponentChessPiece) Promote(square BoardSquare, pieceType ChessPiece) error {
+	scene, err := c.getChessScene()
+	if err != nil {
+		return err
+	}
+	if !scene.IsTurn(c.color) || c.cooldown != 0 {
+		c.promoting = false
+		return nil
+	}
+	position := scene.GetPosition()
+	move := chess.Move{
+		From:      NativeToSquare(c.position),
+		To:        NativeToSquare(square),
+		Promotion: ChessPieceToType(pieceType),
+	}
+	if !position.IsLegal(move) {
+		return fmt.Errorf("can't promote %s from %v to %v as a %s", c.parentActor.GetId(), c.position, square, pieceType)
+	}
+	if err := c.commitMove(move); err != nil {
+		return err
+	}
+	c.promoting = false
+
+	sprite, err := GetSpriteFromPath(PieceSpritePath(c.assetDir, c.color, pieceType))
+	if err != nil {
+		return err
+	}
+	spriteComp, err := GetDrawable(c.parentActor)
+	if err != nil {
+		return err
+	}
+	spriteComp.SetSprite(sprite)
+	c.pieceType = pieceType
+	return nil
+}
+
+// takes whatever's on the capture square off the board, then applies the move to the game
+func (c *ComponentChessPiece) commitMove(move chess.Move) error {
+	scene, err := c.getChessScene()
+	if err != nil {
+		return err
+	}
+	if captureSquare := scene.GetPosition().CaptureSquare(move); captureSquare != chess.NoSquare {
+		capturedActor, err := FindPieceActorAt(scene, SquareToNative(captureSquare))
+		if err != nil {
+			return err
 		}
-	case PieceRook:
-		for i := 0; i < 8; i++ {
-			// move along row
-			availMoves = append(availMoves, BoardSquare{i, pos[1]})
-			// move along col
-			availMoves = append(availMoves, BoardSquare{pos[0], i})
+		if err := scene.RemoveActor(capturedActor.GetId()); err != nil {
+			return err
 		}
-	case PieceBishop:
-		for i := 0; i < 8; i++ {
-			// towards top right
-			availMoves = append(availMoves, BoardSquare{pos[0] + i, pos[1] + i})
-			// towards top left
-			availMoves = append(availMoves, BoardSquare{pos[0] - i, pos[1] + i})
-			// towards bottom left
-			availMoves = append(availMoves, BoardSquare{pos[0] - i, pos[1] - i})
-			// towards bottom right
-			availMoves = append(availMoves, BoardSquare{pos[0] + i, pos[1] - i})
+	}
+	if rookMove, ok := scene.GetPosition().CastlingRookMove(move); ok {
+		rookActor, err := FindPieceActorAt(scene, SquareToNative(rookMove.From))
+		if err != nil {
+			return err
 		}
-	case PieceKnight:
-		// adding counter-clockwise from far right (q1 -> q4)
-		availMoves = append(availMoves,
-			BoardSquare{pos[0] + 2, pos[1] + 1},
-			BoardSquare{pos[0] + 1, pos[1] + 2},
-			BoardSquare{pos[0] - 1, pos[1] + 2},
-			BoardSquare{pos[0] - 2, pos[1] + 1},
-			BoardSquare{pos[0] - 2, pos[1] - 1},
-			BoardSquare{pos[0] - 1, pos[1] - 2},
-			BoardSquare{pos[0] + 1, pos[1] - 2},
-			BoardSquare{pos[0] + 2, pos[1] - 1},
-		)
-	case PieceKing:
-		// counter-clockwise from far right
-		availMoves = append(availMoves,
-			BoardSquare{pos[0] + 1, pos[1] + 0},
-			BoardSquare{pos[0] + 1, pos[1] + 1},
-			BoardSquare{pos[0] + 0, pos[1] + 1},
-			BoardSquare{pos[0] - 1, pos[1] + 1},
-			BoardSquare{pos[0] - 1, pos[1] + 0},
-			BoardSquare{pos[0] - 1, pos[1] - 1},
-			BoardSquare{pos[0] + 0, pos[1] - 1},
-			BoardSquare{pos[0] + 1, pos[1] - 1},
-		)
-	case PieceQueen:
-		for i := 0; i < 8; i++ {
-			// move along row
-			availMoves = append(availMoves, BoardSquare{i, pos[1]})
-			// move along col
-			availMoves = append(availMoves, BoardSquare{pos[0], i})
-
-			// towards top right
-			availMoves = append(availMoves, BoardSquare{pos[0] + i, pos[1] + i})
-			// towards top left
-			availMoves = append(availMoves, BoardSquare{pos[0] - i, pos[1] + i})
-			// towards bottom left
-			availMoves = append(availMoves, BoardSquare{pos[0] - i, pos[1] - i})
-			// towards bottom right
-			availMoves = append(availMoves, BoardSquare{pos[0] + i, pos[1] - i})
+		rookComp, err := GetChessPiece(rookActor)
+		if err != nil {
+			return err
 		}
+		rookComp.PlaceAt(SquareToNative(rookMove.To))
+	}
+	if err := scene.CommitMove(move); err != nil {
+		return err
 	}
+	c.position = SquareToNative(move.To)
+	if scene.GetSettings().Mode == GameModeRealtime {
+		c.cooldown = scene.GetSettings().PieceCooldownTicks
+	}
+	return nil
+}
 
-	// keep only valid moves (i.e. on board)
-	validMoves := make([]BoardSquare, 0)
-	for _, move := range availMoves {
-		if !(move[0] < 0 || move[0] > 7 ||
-			move[1] < 0 || move[1] > 7) {
-			validMoves = append(validMoves, move)
+func (c *ComponentChessPiece) GetAvailableMoves() ([]BoardSquare, error) {
+	position, err := c.getGamePosition()
+	if err != nil {
+		return nil, err
+	}
+	moves := position.MovesFrom(NativeToSquare(c.position))
+	squares := make([]BoardSquare, 0, len(moves))
+	for _, move := range moves {
+		// promotions show up once per piece choice, only mark the square once
+		if move.Promotion != chess.NoPieceType && move.Promotion != chess.Queen {
+			continue
 		}
+		squares = append(squares, SquareToNative(move.To))
 	}
-	return validMoves
+	return squares, nil
 }
 
 func (c *ComponentChessPiece) LockToGrid() error {
-	worldlyComp, err := c.parentActor.GetComponent(ComponentTypeWorldly)
+	worldlyComp, err := GetWorldly(c.parentActor)
 	if err != nil {
 		return err
 	}
 	// see board.go for math
-	worldlyComp.(*ComponentWorldly).SetPosition(
+	worldlyComp.SetPosition(
 		GetBoardDrawingCoords(c.position, PieceWidth, PieceHeight),
 	)
 	return nil
 }
 
-func (c *ComponentChessPiece) Update() error {
+// a piece can be taken off the board by a hit penalty at any time, so it mustn't leave a selection
+// or a promotion picker behind pointing at it
+func (c *ComponentChessPiece) OnRemove() error {
+	scene, err := c.getChessScene()
+	if err != nil {
+		return err
+	}
+	if selection, selected := scene.GetSelection(); selected && selection == c.position {
+		scene.ClearSelection()
+	}
+	if c.promoting {
+		c.promoting = false
+		return ClosePromotionPicker(scene)
+	}
+	return nil
+}
+
+func (c *ComponentChessPiece) Update(sim SimClockInterface) error {
+	if c.cooldown > 0 {
+		c.cooldown--
+	}
+	if c.dragging {
+		return nil
+	}
 	if err := c.LockToGrid(); err != nil {
 		return err
 	}
 	return nil
 }
 
+// a held piece is moved to the cursor once a frame, before the clickable after it checks for hover,
+// otherwise a fast drag leaves the cursor outside the piece and that counts as letting go
+func (c *ComponentChessPiece) UpdateInput() error {
+	if !c.dragging {
+		return nil
+	}
+	return c.followCursor()
+}
+
+func (c *ComponentChessPiece) followCursor() error {
+	worldlyComp, err := GetWorldly(c.parentActor)
+	if err != nil {
+		return err
+	}
+	mx, my := ebiten.CursorPosition()
+	worldlyComp.SetPosition(float64(mx)-PieceWidth/2, float64(my)-PieceHeight/2)
+	return nil
+}
+
 // drawable component for chess piece markers
 type ComponentChessPieceMoveMarker struct {
 	ComponentDrawable
@@ -226,26 +427,44 @@ type ComponentChessPieceMoveMarkerInterface interface {
 }
 
 func NewComponentChessPieceMoveMarker(parent ActorInterface, sprite SpriteInterface, renderLayer RenderLayer) (ComponentChessPieceMoveMarkerInterface, error) {
-	drawable, err := NewComponentDrawable(parent, sprite, renderLayer)
-	if err != nil {
-		return nil, err
-	}
-	drawableComp := drawable.(*ComponentDrawable)
 	component := ComponentChessPieceMoveMarker{
-		ComponentDrawable: *drawableComp,
+		ComponentDrawable: ComponentDrawable{
+			Component:   Component{parent, ComponentTypeDrawable},
+			sprite:      sprite,
+			renderLayer: renderLayer,
+			active:      true,
+		},
 	}
 	return &component, nil
 }
 
-func (c *ComponentChessPieceMoveMarker) Draw(screen *ebiten.Image, renderLayer RenderLayer) error {
+// in click input mode the markers follow the scene's selection rather than the mouse
+func (c *ComponentChessPieceMoveMarker) Update(sim SimClockInterface) error {
+	scene, ok := c.parentActor.GetParentScene().(ChessSceneInterface)
+	if !ok || scene.GetSettings().InputMode != InputModeClick {
+		return nil
+	}
+	chessComp, err := GetChessPiece(c.parentActor)
+	if err != nil {
+		return err
+	}
+	selection, selected := scene.GetSelection()
+	c.SetActive(selected && selection == chessComp.GetPosition())
+	return nil
+}
+
+func (c *ComponentChessPieceMoveMarker) Draw(screen *ebiten.Image, renderLayer RenderLayer, sim SimClockInterface) error {
 	if !c.CheckIfDrawable(renderLayer) {
 		return nil
 	}
-	chessComp, err := c.parentActor.GetComponent(ComponentTypeChessPiece)
+	chessComp, err := GetChessPiece(c.parentActor)
+	if err != nil {
+		return err
+	}
+	moves, err := chessComp.GetAvailableMoves()
 	if err != nil {
 		return err
 	}
-	moves := chessComp.(ComponentChessPieceInterface).GetAvailableMoves()
 	for _, square := range moves {
 		drawX, drawY := GetBoardDrawingCoords(square, MarkerWidth, MarkerHeight)
 		if err := c.sprite.Draw(screen, drawX, drawY, MarkerWidth, MarkerHeight, 0); err != nil {
@@ -257,6 +476,39 @@ func (c *ComponentChessPieceMoveMarker) Draw(screen *ebiten.Image, renderLayer R
 
 const ActorTypeChessPiece = "actor-chess-piece"
 
+// where the piece sprites for the board are
+const PieceAssetDir = "assets/sprites/chessboard/chess_green/"
+
+func PieceSpritePath(assetDir string, color BoardSide, pieceType ChessPiece) string {
+	return assetDir + "/" + string(color) + "_" + string(pieceType) + ".png"
+}
+
+// the piece actor standing on a square, if there is one
+func PieceActorAt(scene SceneInterface, square BoardSquare) (ActorInterface, bool, error) {
+	for _, actor := range scene.GetActorsType(ActorTypeChessPiece) {
+		pieceComp, err := GetChessPiece(actor)
+		if err != nil {
+			return nil, false, err
+		}
+		if pieceComp.GetPosition() == square {
+			return actor, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// same as PieceActorAt, but for when there has to be a piece there
+func FindPieceActorAt(scene SceneInterface, square BoardSquare) (ActorInterface, error) {
+	actor, found, err := PieceActorAt(scene, square)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("no piece found on square %v", square)
+	}
+	return actor, nil
+}
+
 func NewActorChessPiece(parentScene SceneInterface, color BoardSide, pieceType ChessPiece,
 	position BoardSquare, assetDir string) (ActorInterface, error) {
 
@@ -267,8 +519,7 @@ func NewActorChessPiece(parentScene SceneInterface, color BoardSide, pieceType C
 		components:  make([]ComponentInterface, 0),
 	}
 
-	spriteFile := string(color) + "_" + string(pieceType) + ".png"
-	sprite, err := NewBasicSpriteFromPath(assetDir + "/" + spriteFile)
+	sprite, err := GetSpriteFromPath(PieceSpritePath(assetDir, color, pieceType))
 	if err != nil {
 		return nil, err
 	}
@@ -278,7 +529,7 @@ func NewActorChessPiece(parentScene SceneInterface, color BoardSide, pieceType C
 	}
 	actor.components = append(actor.components, spriteComp)
 
-	markerSprite, err := NewBasicSpriteFromPath("assets/sprites/marker.png")
+	markerSprite, err := GetSpriteFromPath("assets/sprites/marker.png")
 	if err != nil {
 		return nil, err
 	}
@@ -295,7 +546,7 @@ func NewActorChessPiece(parentScene SceneInterface, color BoardSide, pieceType C
 	}
 	actor.components = append(actor.components, worldly)
 
-	pieceComp, err := NewComponentChessPiece(&actor, color, pieceType, position)
+	pieceComp, err := NewComponentChessPiece(&actor, color, pieceType, position, assetDir)
 	if err != nil {
 		return nil, err
 	}
@@ -306,14 +557,89 @@ func NewActorChessPiece(parentScene SceneInterface, color BoardSide, pieceType C
 		return nil, err
 	}
 	clickableComp.AddStateListener(MouseStatePressed, func() error {
-		markerSpriteComp.SetActive(true)
+		// clicks are handled by the board in click input mode
+		if scene, ok := parentScene.(ChessSceneInterface); ok && scene.GetSettings().InputMode == InputModeClick {
+			return nil
+		}
+		// the other side's pieces (or ones still cooling down) can't be picked up
+		pickedUp, err := pieceComp.PickUp()
+		if err != nil {
+			return err
+		}
+		markerSpriteComp.SetActive(pickedUp)
 		return nil
 	})
+	// every piece hears every release, only the one being held cares
 	clickableComp.AddStateListener(MouseStateReleased, func() error {
+		if !pieceComp.IsDragging() {
+			return nil
+		}
 		markerSpriteComp.SetActive(false)
-		return nil
+		mx, my := ebiten.CursorPosition()
+		_, err := pieceComp.Drop(float64(mx), float64(my))
+		return err
 	})
 	actor.components = append(actor.components, clickableComp)
 
 	return &actor, nil
 }
+
+// an actor for every piece on the board
+func AddPieceActors(parentScene SceneInterface, position *chess.Position, assetDir string) error {
+	for sq := chess.Square(0); sq < 64; sq++ {
+		piece := position.PieceAt(sq)
+		if piece.IsEmpty() {
+			continue
+		}
+		actor, err := NewActorChessPiece(parentScene, ColorToBoardSide(piece.Color), TypeToChessPiece(piece.Type),
+			SquareToNative(sq), assetDir)
+		if err != nil {
+			return err
+		}
+		if err := parentScene.AddActor(actor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// brings the piece actors back in line with a position that didn't come from moves on the board (undo/redo):
+// pieces already on the right square stay put, the rest are taken off and made again
+func SyncPieceActors(parentScene SceneInterface, position *chess.Position, assetDir string) error {
+	kept := make(map[chess.Square]bool)
+	// removing can shuffle the scene's own slice, so go through a copy
+	actors := append([]ActorInterface(nil), parentScene.GetActorsType(ActorTypeChessPiece)...)
+	for _, actor := range actors {
+		pieceComp, err := GetChessPiece(actor)
+		if err != nil {
+			return err
+		}
+		square := NativeToSquare(pieceComp.GetPosition())
+		piece := chess.Piece{
+			Color: BoardSideToColor(pieceComp.GetColor()),
+			Type:  ChessPieceToType(pieceComp.GetPieceType()),
+		}
+		if position.PieceAt(square) == piece && !kept[square] {
+			kept[square] = true
+			continue
+		}
+		if err := parentScene.RemoveActor(actor.GetId()); err != nil {
+			return err
+		}
+	}
+	for sq := chess.Square(0); sq < 64; sq++ {
+		piece := position.PieceAt(sq)
+		if piece.IsEmpty() || kept[sq] {
+			continue
+		}
+		actor, err := NewActorChessPiece(parentScene, ColorToBoardSide(piece.Color), TypeToChessPiece(piece.Type),
+			SquareToNative(sq), assetDir)
+		if err != nil {
+			return err
+		}
+		if err := parentScene.AddActor(actor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
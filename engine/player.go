@@ -0,0 +1,268 @@
+package engine
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/val-is/bullet-hell-chess/chess"
+	"github.com/val-is/bullet-hell-chess/collision"
+	"github.com/val-is/bullet-hell-chess/ecs"
+)
+
+var PlayerCursorColor = color.RGBA{0x40, 0xc0, 0xff, 0xff}
+
+// how often the cursor blinks while invulnerable
+const InvulnerabilityBlinkTicks = 6
+
+// lives and the grace period after getting hit
+const ComponentTypeHealth = "component-health"
+
+type ComponentHealth struct {
+	Component
+	// lives <= 0 with maxLives == 0 just means nobody's counting
+	lives                int
+	maxLives             int
+	invulnerabilityTicks int
+	invulnerableFor      int
+}
+
+type ComponentHealthInterface interface {
+	ComponentInterface
+	GetLives() int
+	IsInvulnerable() bool
+	GetInvulnerableTicks() int
+	// true if the hit counted, i.e. the player wasn't invulnerable
+	Hit() bool
+	IsDead() bool
+}
+
+func NewComponentHealth(parent ActorInterface, lives, invulnerabilityTicks int) (ComponentHealthInterface, error) {
+	return &ComponentHealth{
+		Component:            Component{parent, ComponentTypeHealth},
+		lives:                lives,
+		maxLives:             lives,
+		invulnerabilityTicks: invulnerabilityTicks,
+	}, nil
+}
+
+func GetHealth(actor ActorInterface) (ComponentHealthInterface, error) {
+	component, err := actor.GetComponent(ComponentTypeHealth)
+	if err != nil {
+		return nil, err
+	}
+	health, ok := component.(ComponentHealthInterface)
+	if !ok {
+		return nil, wrongComponentError(actor, component, "ComponentHealthInterface")
+	}
+	return health, nil
+}
+
+func (c *ComponentHealth) GetLives() int {
+	return c.lives
+}
+
+func (c *ComponentHealth) IsInvulnerable() bool {
+	return c.invulnerableFor > 0
+}
+
+func (c *ComponentHealth) GetInvulnerableTicks() int {
+	return c.invulnerableFor
+}
+
+func (c *ComponentHealth) Hit() bool {
+	if c.IsInvulnerable() {
+		return false
+	}
+	if c.maxLives > 0 && c.lives > 0 {
+		c.lives--
+	}
+	c.invulnerableFor = c.invulnerabilityTicks
+	return true
+}
+
+func (c *ComponentHealth) IsDead() bool {
+	return c.maxLives > 0 && c.lives <= 0
+}
+
+func (c *ComponentHealth) Update(sim SimClockInterface) error {
+	if c.invulnerableFor > 0 {
+		c.invulnerableFor--
+	}
+	return nil
+}
+
+// follows the mouse for whichever side is holding it, and checks it against every bullet
+const ComponentTypePlayerCursor = "component-player-cursor"
+
+type ComponentPlayerCursor struct {
+	Component
+	side   BoardSide
+	radius float64
+	// both made once up front, building them every tick would allocate
+	hitbox collision.Circle
+	onHit  func(actor ActorInterface, part uint64) (bool, error)
+}
+
+type ComponentPlayerCursorInterface interface {
+	ComponentInterface
+	GetSide() BoardSide
+	GetRadius() float64
+	IsActive() (bool, error)
+}
+
+func NewComponentPlayerCursor(parent ActorInterface, side BoardSide, radius float64) (ComponentPlayerCursorInterface, error) {
+	cursor := &ComponentPlayerCursor{
+		Component: Component{parent, ComponentTypePlayerCursor},
+		side:      side,
+		radius:    radius,
+	}
+	cursor.onHit = cursor.hitBy
+	return cursor, nil
+}
+
+func (c *ComponentPlayerCursor) GetSide() BoardSide {
+	return c.side
+}
+
+func (c *ComponentPlayerCursor) GetRadius() float64 {
+	return c.radius
+}
+
+func (c *ComponentPlayerCursor) getChessScene() (ChessSceneInterface, error) {
+	scene, ok := c.parentActor.GetParentScene().(ChessSceneInterface)
+	if !ok {
+		return nil, fmt.Errorf("cursor %s is not part of a chess scene", c.parentActor.GetId())
+	}
+	return scene, nil
+}
+
+// there's only one mouse, so it belongs to whoever's move it is
+func (c *ComponentPlayerCursor) IsActive() (bool, error) {
+	scene, err := c.getChessScene()
+	if err != nil {
+		return false, err
+	}
+	return !scene.GetOutcome().IsOver() && scene.GetTurn() == c.side, nil
+}
+
+func (c *ComponentPlayerCursor) Update(sim SimClockInterface) error {
+	active, err := c.IsActive()
+	if err != nil {
+		return err
+	}
+	sprite, err := GetDrawable(c.parentActor)
+	if err != nil {
+		return err
+	}
+	health, err := GetHealth(c.parentActor)
+	if err != nil {
+		return err
+	}
+
+	blinkOff := health.IsInvulnerable() && (health.GetInvulnerableTicks()/InvulnerabilityBlinkTicks)%2 == 1
+	sprite.SetActive(active && !blinkOff)
+	if !active {
+		return nil
+	}
+
+	mx, my := ebiten.CursorPosition()
+	worldly, err := GetWorldly(c.parentActor)
+	if err != nil {
+		return err
+	}
+	worldly.SetPosition(float64(mx)-c.radius, float64(my)-c.radius)
+
+	c.hitbox = collision.Circle{X: float64(mx), Y: float64(my), R: c.radius}
+	return c.parentActor.GetParentScene().QueryColliders(&c.hitbox, c.onHit)
+}
+
+// bullets that hit are used up, even if the player was invulnerable at the time
+// they're either actors of their own or one of the bullet field's, where part is the bullet's entity
+func (c *ComponentPlayerCursor) hitBy(actor ActorInterface, part uint64) (bool, error) {
+	scene, err := c.getChessScene()
+	if err != nil {
+		return false, err
+	}
+	switch actor.GetActorType() {
+	case ActorTypeBullet:
+		if err := scene.RemoveActor(actor.GetId()); err != nil {
+			return false, err
+		}
+	case ActorTypeBulletField:
+		field, err := GetBulletField(actor)
+		if err != nil {
+			return false, err
+		}
+		if !field.Kill(ecs.Entity(part)) {
+			return true, nil
+		}
+	default:
+		return true, nil
+	}
+
+	health, err := GetHealth(c.parentActor)
+	if err != nil {
+		return false, err
+	}
+	if !health.Hit() {
+		return true, nil
+	}
+	if err := scene.RecordHit(c.side); err != nil {
+		return false, err
+	}
+	if health.IsDead() {
+		scene.SetOutcome(chess.Outcome{
+			Result:      chess.WinFor(BoardSideToColor(c.side).Other()),
+			Termination: chess.TerminationLives,
+		})
+	}
+	return true, nil
+}
+
+const ActorTypePlayerCursor = "actor-player-cursor"
+
+func PlayerCursorId(side BoardSide) string {
+	return "player-cursor-" + string(side)
+}
+
+func NewActorPlayerCursor(parentScene SceneInterface, side BoardSide, settings GameSettings) (ActorInterface, error) {
+	actor := Actor{
+		parentScene: parentScene,
+		actorType:   ActorTypePlayerCursor,
+		id:          PlayerCursorId(side),
+		components:  make([]ComponentInterface, 0),
+	}
+
+	radius := settings.CursorHitboxRadius
+	sprite, err := GetCircleSprite(int(math.Ceil(radius)), PlayerCursorColor)
+	if err != nil {
+		return nil, err
+	}
+	spriteComp, err := NewComponentDrawable(&actor, sprite, RenderLayerUI)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, spriteComp)
+
+	worldly, err := NewComponentWorldly(&actor, 0, 0, 2*radius, 2*radius, 0)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, worldly)
+
+	health, err := NewComponentHealth(&actor, settings.Lives, settings.InvulnerabilityTicks)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, health)
+
+	cursor, err := NewComponentPlayerCursor(&actor, side, radius)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, cursor)
+
+	return &actor, nil
+}
@@ -0,0 +1,40 @@
+package engine
+
+// components that keep state from one life to the next implement this, so a recycled actor starts out fresh
+type ResettableInterface interface {
+	Reset()
+}
+
+// free list of removed actors of one type, so things spawned constantly (bullets) don't get allocated every time
+type ActorPool struct {
+	free []ActorInterface
+}
+
+func NewActorPool() *ActorPool {
+	return &ActorPool{
+		free: make([]ActorInterface, 0),
+	}
+}
+
+// false if nothing is free, the caller should make a new actor then
+func (p *ActorPool) Get() (ActorInterface, bool) {
+	if len(p.free) == 0 {
+		return nil, false
+	}
+	actor := p.free[len(p.free)-1]
+	p.free[len(p.free)-1] = nil
+	p.free = p.free[:len(p.free)-1]
+	return actor, true
+}
+
+// resets the actor on the way in
+func (p *ActorPool) Put(actor ActorInterface) {
+	if resettable, ok := actor.(ResettableInterface); ok {
+		resettable.Reset()
+	}
+	p.free = append(p.free, actor)
+}
+
+func (p *ActorPool) Len() int {
+	return len(p.free)
+}
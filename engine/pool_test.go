@@ -0,0 +1,125 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+	"github.com/val-is/bullet-hell-chess/collision"
+	"github.com/val-is/bullet-hell-chess/pattern"
+)
+
+// a scene with the bullet field, a cursor to hit (it sits at 0, 0 without a mouse) and emitters firing
+// every kind of pattern next to it, run until the pools and the collider grid are as big as they'll get
+func newSteadyBulletScene(t *testing.T) (SceneInterface, SimClockInterface) {
+	settings := DefaultGameSettings()
+	settings.Lives = 1000000
+	settings.TimeControl = chess.Untimed
+	scene, err := NewChessScene(chess.NewStartingPosition(), settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	field, err := NewActorBulletField(scene)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := scene.AddActor(field); err != nil {
+		t.Fatal(err)
+	}
+	cursor, err := NewActorPlayerCursor(scene, BoardSideWhite, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := scene.AddActor(cursor); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []pattern.Emitter{
+		{Type: pattern.Radial, Count: 40, Speed: 3, Radius: 4, Interval: 2, Lifetime: 90},
+		{Type: pattern.Spiral, Count: 8, Speed: 4, Radius: 3, AngleStep: 0.03, Interval: 1, Lifetime: 100},
+		{Type: pattern.Aimed, Count: 5, Speed: 5, Spread: 0.2, Radius: 3, Interval: 3, Lifetime: 60,
+			Children: []pattern.Emitter{{Type: pattern.Radial, Count: 3, Speed: 2, Radius: 2, Delay: 30, Interval: 100, Repeat: 1}}},
+	} {
+		emitter, err := NewActorEmitter(scene, 40, 40, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := scene.AddActor(emitter); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sim, err := NewSimClock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3000; i++ {
+		if err := scene.Update(sim); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return scene, sim
+}
+
+func TestBulletSteadyStateAllocs(t *testing.T) {
+	scene, sim := newSteadyBulletScene(t)
+
+	allocs := testing.AllocsPerRun(300, func() {
+		if err := scene.Update(sim); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("bullet update: %v allocs per tick, want 0", allocs)
+	}
+}
+
+func TestBulletPoolAllocs(t *testing.T) {
+	scene, _ := newSteadyBulletScene(t)
+	pool := scene.GetPool(ActorTypeBullet)
+	if pool.Len() == 0 {
+		t.Fatal("no bullets were pooled after 3000 ticks")
+	}
+
+	children := []pattern.Emitter{{Type: pattern.Radial, Count: 3, Speed: 2, Radius: 2, Delay: 30, Interval: 100, Repeat: 1}}
+	allocs := testing.AllocsPerRun(300, func() {
+		bullet, err := NewActorBullet(scene, 100, 100, 3, 1, 0, 0, 0, 60, children)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pool.Put(bullet)
+
+		bare, ok := pool.Get()
+		if !ok {
+			t.Fatal("pool was empty right after a put")
+		}
+		pool.Put(bare)
+	})
+	if allocs != 0 {
+		t.Errorf("pool get/put: %v allocs per run, want 0", allocs)
+	}
+}
+
+func TestColliderAllocs(t *testing.T) {
+	scene, sim := newSteadyBulletScene(t)
+	actors := scene.GetActorsType(ActorTypeBullet)
+	if len(actors) == 0 {
+		t.Fatal("no bullets in the scene after 3000 ticks")
+	}
+
+	shape := &collision.Circle{X: 200, Y: 200, R: 4}
+	register := func() {
+		if err := scene.Update(sim); err != nil {
+			t.Fatal(err)
+		}
+		for _, actor := range actors {
+			scene.AddCollider(shape, actor, 0)
+		}
+	}
+	// one round so the grid's slices have room for the extra shapes
+	register()
+
+	allocs := testing.AllocsPerRun(300, register)
+	if allocs != 0 {
+		t.Errorf("collider registration: %v allocs per tick, want 0", allocs)
+	}
+}
@@ -0,0 +1,97 @@
+package engine
+
+import (
+	"image/color"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+)
+
+var PromotionBackdropColor = color.RGBA{0xee, 0xee, 0xd2, 0xff}
+
+// one of the pieces offered when a pawn reaches the last rank
+const ActorTypePromotionOption = "actor-promotion-option"
+
+// lays the choices out in a column from the promotion square towards the middle of the board
+func OpenPromotionPicker(parentScene SceneInterface, pawn ActorInterface, color BoardSide, square BoardSquare, assetDir string) error {
+	dir := 1
+	if square[1] != 0 {
+		dir = -1
+	}
+	for i, promotion := range chess.PromotionPieces {
+		option, err := NewActorPromotionOption(parentScene, pawn, color, TypeToChessPiece(promotion),
+			square, BoardSquare{square[0], square[1] + dir*i}, assetDir)
+		if err != nil {
+			return err
+		}
+		if err := parentScene.AddActor(option); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ClosePromotionPicker(parentScene SceneInterface) error {
+	options := append([]ActorInterface(nil), parentScene.GetActorsType(ActorTypePromotionOption)...)
+	for _, option := range options {
+		if err := parentScene.RemoveActor(option.GetId()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func NewActorPromotionOption(parentScene SceneInterface, pawn ActorInterface, color BoardSide, pieceType ChessPiece,
+	promotionSquare, displaySquare BoardSquare, assetDir string) (ActorInterface, error) {
+
+	actor := Actor{
+		parentScene: parentScene,
+		actorType:   ActorTypePromotionOption,
+		id:          NewId("promotion-" + string(pieceType)),
+		components:  make([]ComponentInterface, 0),
+	}
+
+	backdrop, err := NewColorSprite(int(PieceSpriteWidth), int(PieceSpriteHeight), PromotionBackdropColor)
+	if err != nil {
+		return nil, err
+	}
+	backdropComp, err := NewComponentDrawable(&actor, backdrop, RenderLayerUI)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, backdropComp)
+
+	sprite, err := GetSpriteFromPath(PieceSpritePath(assetDir, color, pieceType))
+	if err != nil {
+		return nil, err
+	}
+	spriteComp, err := NewComponentDrawable(&actor, sprite, RenderLayerUI)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, spriteComp)
+
+	x, y := GetBoardDrawingCoords(displaySquare, PieceWidth, PieceHeight)
+	worldly, err := NewComponentWorldly(&actor, x, y, PieceWidth, PieceHeight, 0)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, worldly)
+
+	clickableComp, err := NewComponentClickable(&actor)
+	if err != nil {
+		return nil, err
+	}
+	clickableComp.AddStateListener(MouseStatePressed, func() error {
+		pieceComp, err := GetChessPiece(pawn)
+		if err != nil {
+			return err
+		}
+		if err := pieceComp.Promote(promotionSquare, pieceType); err != nil {
+			return err
+		}
+		return ClosePromotionPicker(parentScene)
+	})
+	actor.components = append(actor.components, clickableComp)
+
+	return &actor, nil
+}
@@ -0,0 +1,47 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+)
+
+// a white pawn on e7 with its picker open
+func newPromotingPawn(t *testing.T) (ChessSceneInterface, *ComponentChessPiece, BoardSquare) {
+	scene := newTestChessScene(t, "k7/4P3/8/8/8/8/8/4K3 w - - 0 1")
+	pawn := newBarePiece(t, scene, BoardSideWhite, PiecePawn, "e7")
+	pawn.promoting = true
+	to, err := ParseBoardSquare("e8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return scene, pawn, to
+}
+
+func TestPromoteWhenItCantMove(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		before func(scene ChessSceneInterface)
+	}{
+		{"game over", func(scene ChessSceneInterface) {
+			scene.SetOutcome(chess.Outcome{Result: chess.ResultBlackWins, Termination: chess.TerminationTimeout})
+		}},
+		{"turn lost", func(scene ChessSceneInterface) {
+			scene.GetGame().Pass()
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			scene, pawn, to := newPromotingPawn(t)
+			tc.before(scene)
+			if err := pawn.Promote(to, PieceQueen); err != nil {
+				t.Fatal(err)
+			}
+			if moves := scene.GetGame().Moves; len(moves) != 0 {
+				t.Errorf("promotion was played: %v", moves)
+			}
+			if pawn.promoting || pawn.pieceType != PiecePawn {
+				t.Errorf("pawn is still promoting or changed to %s", pawn.pieceType)
+			}
+		})
+	}
+}
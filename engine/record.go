@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+	"github.com/val-is/bullet-hell-chess/notation"
+)
+
+// finished games are written here unless the settings say otherwise
+const DefaultPGNDir = "games"
+
+const PGNEvent = "Bullet Hell Chess"
+
+// the variant tag spells out the settings that change how the game plays
+func (s *ChessScene) pgnVariant() string {
+	variant := fmt.Sprintf("bullet hell, %s, %s penalty", s.settings.Mode, s.settings.HitPenalty)
+	if s.settings.Lives > 0 {
+		variant += fmt.Sprintf(", %d lives", s.settings.Lives)
+	}
+	return variant
+}
+
+// pgn wants seconds+seconds, or - for no clock
+func pgnTimeControl(control chess.TimeControl) string {
+	if !control.IsTimed() {
+		return "-"
+	}
+	return strconv.FormatFloat(control.Base.Seconds(), 'f', -1, 64) + "+" +
+		strconv.FormatFloat(control.Increment.Seconds(), 'f', -1, 64)
+}
+
+func (s *ChessScene) GetPGN() string {
+	tags := []notation.PGNTag{
+		{Name: "Event", Value: PGNEvent},
+		{Name: "Site", Value: "?"},
+		{Name: "Date", Value: s.started.Format("2006.01.02")},
+		{Name: "Round", Value: "-"},
+		{Name: "White", Value: s.settings.WhiteName},
+		{Name: "Black", Value: s.settings.BlackName},
+		{Name: "TimeControl", Value: pgnTimeControl(s.settings.TimeControl)},
+		{Name: "Variant", Value: s.pgnVariant()},
+		{Name: "WhiteBulletHits", Value: strconv.Itoa(s.hits[BoardSideWhite])},
+		{Name: "BlackBulletHits", Value: strconv.Itoa(s.hits[BoardSideBlack])},
+	}
+	comment := ""
+	if s.outcome.IsOver() {
+		comment = s.outcome.String()
+	}
+	return notation.FormatPGN(s.game, tags, s.outcome.Result, comment)
+}
+
+// writes the game to a new file in dir, named after when it started
+func (s *ChessScene) SavePGN(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	filename := filepath.Join(dir, s.started.Format("2006-01-02_15-04-05")+".pgn")
+	if err := ioutil.WriteFile(filename, []byte(s.GetPGN()), 0644); err != nil {
+		return "", err
+	}
+	return filename, nil
+}
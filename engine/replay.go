@@ -0,0 +1,551 @@
+package engine
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/inpututil"
+	"github.com/val-is/bullet-hell-chess/chess"
+	"github.com/val-is/bullet-hell-chess/notation"
+)
+
+const ReplaySceneId = "scene-replay"
+
+// how long a piece takes to slide to its new square
+const ReplaySlideTicks = 12
+
+// the text around the board, the move list above it and the sidebar with comments and variations below
+const (
+	ReplayTextMargin    = 8.0
+	ReplayMoveListLines = 4
+	// what fits across the screen in the debug font
+	ReplayTextWidthChars = 80
+)
+
+// every game in the file, parse errors come back as file:line:column: problem
+func LoadPGNFile(filename string) ([]*notation.PGNGame, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	games, err := notation.ParsePGN(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s:%s", filename, err)
+	}
+	if len(games) == 0 {
+		return nil, fmt.Errorf("%s: no games in the file", filename)
+	}
+	return games, nil
+}
+
+// moves its actor to a new spot over a few ticks instead of all at once
+const ComponentTypeSlide = "component-slide"
+
+type ComponentSlide struct {
+	Component
+	fromX, fromY float64
+	toX, toY     float64
+	ticks        int
+	duration     int
+}
+
+type ComponentSlideInterface interface {
+	ComponentInterface
+	SlideTo(x, y float64, duration int) error
+}
+
+func NewComponentSlide(parent ActorInterface) (ComponentSlideInterface, error) {
+	return &ComponentSlide{
+		Component: Component{parent, ComponentTypeSlide},
+	}, nil
+}
+
+func GetSlide(actor ActorInterface) (ComponentSlideInterface, error) {
+	component, err := actor.GetComponent(ComponentTypeSlide)
+	if err != nil {
+		return nil, err
+	}
+	slide, ok := component.(ComponentSlideInterface)
+	if !ok {
+		return nil, wrongComponentError(actor, component, "ComponentSlideInterface")
+	}
+	return slide, nil
+}
+
+// starts from wherever the actor is right now, so a slide can be redirected halfway through
+func (c *ComponentSlide) SlideTo(x, y float64, duration int) error {
+	worldly, err := GetWorldly(c.parentActor)
+	if err != nil {
+		return err
+	}
+	c.fromX, c.fromY = worldly.GetPosition()
+	c.toX, c.toY = x, y
+	c.ticks = 0
+	c.duration = duration
+	return nil
+}
+
+func (c *ComponentSlide) Update(sim SimClockInterface) error {
+	if c.ticks >= c.duration {
+		return nil
+	}
+	worldly, err := GetWorldly(c.parentActor)
+	if err != nil {
+		return err
+	}
+	c.ticks++
+	t := float64(c.ticks) / float64(c.duration)
+	// eases in and out
+	t = t * t * (3 - 2*t)
+	worldly.MovePosition(Lerp(c.fromX, c.toX, t), Lerp(c.fromY, c.toY, t))
+	return nil
+}
+
+// piece that only gets looked at, moved around by the replay rather than by the player
+const ActorTypeReplayPiece = "actor-replay-piece"
+
+func NewActorReplayPiece(parentScene SceneInterface, piece chess.Piece, square chess.Square, assetDir string) (ActorInterface, error) {
+	actor := Actor{
+		parentScene: parentScene,
+		actorType:   ActorTypeReplayPiece,
+		id:          NewId("replay-piece"),
+		components:  make([]ComponentInterface, 0),
+	}
+
+	sprite, err := GetSpriteFromPath(replayPieceSpritePath(assetDir, piece))
+	if err != nil {
+		return nil, err
+	}
+	spriteComp, err := NewComponentDrawable(&actor, sprite, RenderLayerForegroundObject)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, spriteComp)
+
+	x, y := GetBoardDrawingCoords(SquareToNative(square), PieceWidth, PieceHeight)
+	worldly, err := NewComponentWorldly(&actor, x, y, PieceWidth, PieceHeight, 0)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, worldly)
+
+	slide, err := NewComponentSlide(&actor)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, slide)
+
+	return &actor, nil
+}
+
+func replayPieceSpritePath(assetDir string, piece chess.Piece) string {
+	return PieceSpritePath(assetDir, ColorToBoardSide(piece.Color), TypeToChessPiece(piece.Type))
+}
+
+// steps through a game from a pgn. right/left go forward and back, up/down pick which variation right goes
+// into, home/end jump to the start and end of the line, page up/down switch games if the file has more than one
+type ReplayScene struct {
+	Scene
+	games     []*notation.PGNGame
+	gameIndex int
+	node      *notation.PGNNode
+	// which of node's children right goes to, 0 is carrying on along the current line
+	choice   int
+	assetDir string
+	// piece actors by the square they're on (or heading to), and the position they were set out for
+	pieces   map[chess.Square]ActorInterface
+	placed   *chess.Position
+	header   TextSpriteInterface
+	moveList TextSpriteInterface
+	sidebar  TextSpriteInterface
+}
+
+type ReplaySceneInterface interface {
+	SceneInterface
+	GetGame() *notation.PGNGame
+	GetNode() *notation.PGNNode
+	GoTo(node *notation.PGNNode) error
+	StepForward() error
+	StepBack() error
+	SelectVariation(offset int)
+	SelectGame(index int) error
+}
+
+func NewReplayScene(filename string) (ReplaySceneInterface, error) {
+	games, err := LoadPGNFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	s := &ReplayScene{
+		Scene:    newBaseScene(),
+		games:    games,
+		assetDir: PieceAssetDir,
+		pieces:   make(map[chess.Square]ActorInterface),
+	}
+
+	bgActor, err := NewActorBackgroundImage(s, "scene-background", "assets/sprites/chessboard/chess_green/bg.png")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.AddActor(bgActor); err != nil {
+		return nil, err
+	}
+	boardActor, err := NewActorBoard(s, "board-actor", "assets/sprites/chessboard/chess_green/board.png")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.AddActor(boardActor); err != nil {
+		return nil, err
+	}
+
+	addText := func(id string, y float64) (TextSpriteInterface, error) {
+		text, err := NewTextSprite("")
+		if err != nil {
+			return nil, err
+		}
+		actor, err := NewActorText(s, id, text, ReplayTextMargin, y, RenderLayerUI)
+		if err != nil {
+			return nil, err
+		}
+		return text, s.AddActor(actor)
+	}
+	if s.header, err = addText("replay-header", ReplayTextMargin); err != nil {
+		return nil, err
+	}
+	if s.moveList, err = addText("replay-move-list", ReplayTextMargin+2*TextLineHeight); err != nil {
+		return nil, err
+	}
+	if s.sidebar, err = addText("replay-sidebar", (ScreenHeight+BoardHeight)/2+ReplayTextMargin); err != nil {
+		return nil, err
+	}
+
+	if err := s.SelectGame(0); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *ReplayScene) GetGame() *notation.PGNGame {
+	return s.games[s.gameIndex]
+}
+
+func (s *ReplayScene) GetNode() *notation.PGNNode {
+	return s.node
+}
+
+func (s *ReplayScene) SelectGame(index int) error {
+	if index < 0 || index >= len(s.games) {
+		return nil
+	}
+	s.gameIndex = index
+	// a different game could start from anywhere, so its pieces are set out from scratch
+	for square, actor := range s.pieces {
+		if err := s.RemoveActor(actor.GetId()); err != nil {
+			return err
+		}
+		delete(s.pieces, square)
+	}
+	s.placed = nil
+	return s.GoTo(s.GetGame().Root)
+}
+
+func (s *ReplayScene) GoTo(node *notation.PGNNode) error {
+	s.node = node
+	s.choice = 0
+	if err := s.syncPieces(node.Position); err != nil {
+		return err
+	}
+	s.updateText()
+	return nil
+}
+
+func (s *ReplayScene) StepForward() error {
+	if len(s.node.Children) == 0 {
+		return nil
+	}
+	return s.GoTo(s.node.Children[s.choice])
+}
+
+// going back keeps whatever line we came down selected, so right goes straight back into it
+func (s *ReplayScene) StepBack() error {
+	if s.node.Parent == nil {
+		return nil
+	}
+	from := s.node
+	if err := s.GoTo(s.node.Parent); err != nil {
+		return err
+	}
+	for i, child := range s.node.Children {
+		if child == from {
+			s.choice = i
+		}
+	}
+	s.updateText()
+	return nil
+}
+
+func (s *ReplayScene) SelectVariation(offset int) {
+	if len(s.node.Children) == 0 {
+		return
+	}
+	s.choice = (s.choice + offset + len(s.node.Children)) % len(s.node.Children)
+	s.updateText()
+}
+
+// as few pieces as possible change: anything that's still on its square stays put, pieces that have moved
+// slide over from wherever the same piece (or a pawn, for promotions) was, and only what's left over
+// appears or disappears
+func (s *ReplayScene) syncPieces(position *chess.Position) error {
+	gone := make(map[chess.Square]ActorInterface)
+	arrived := make([]chess.Square, 0)
+	current := make(map[chess.Square]chess.Piece)
+	for square, actor := range s.pieces {
+		piece := s.placed.PieceAt(square)
+		current[square] = piece
+		if position.PieceAt(square) != piece {
+			gone[square] = actor
+			delete(s.pieces, square)
+		}
+	}
+	for sq := chess.Square(0); sq < 64; sq++ {
+		if piece := position.PieceAt(sq); !piece.IsEmpty() && current[sq] != piece {
+			arrived = append(arrived, sq)
+		}
+	}
+
+	// the same piece first, then a piece of the same colour that's changed into it
+	matchers := []func(from, to chess.Piece) bool{
+		func(from, to chess.Piece) bool { return from == to },
+		func(from, to chess.Piece) bool {
+			return from.Color == to.Color && (from.Type == chess.Pawn || to.Type == chess.Pawn)
+		},
+	}
+	for _, matches := range matchers {
+		remaining := arrived[:0]
+		for _, to := range arrived {
+			piece := position.PieceAt(to)
+			from, found := chess.NoSquare, false
+			for square := range gone {
+				if !matches(current[square], piece) {
+					continue
+				}
+				if !found || squareDistance(square, to) < squareDistance(from, to) {
+					from, found = square, true
+				}
+			}
+			if !found {
+				remaining = append(remaining, to)
+				continue
+			}
+			actor := gone[from]
+			delete(gone, from)
+			if err := s.slidePiece(actor, piece, to); err != nil {
+				return err
+			}
+		}
+		arrived = remaining
+	}
+
+	for _, actor := range gone {
+		if err := s.RemoveActor(actor.GetId()); err != nil {
+			return err
+		}
+	}
+	for _, square := range arrived {
+		actor, err := NewActorReplayPiece(s, position.PieceAt(square), square, s.assetDir)
+		if err != nil {
+			return err
+		}
+		if err := s.AddActor(actor); err != nil {
+			return err
+		}
+		s.pieces[square] = actor
+	}
+	s.placed = position
+	return nil
+}
+
+func (s *ReplayScene) slidePiece(actor ActorInterface, piece chess.Piece, to chess.Square) error {
+	sprite, err := GetSpriteFromPath(replayPieceSpritePath(s.assetDir, piece))
+	if err != nil {
+		return err
+	}
+	drawable, err := GetDrawable(actor)
+	if err != nil {
+		return err
+	}
+	drawable.SetSprite(sprite)
+	slide, err := GetSlide(actor)
+	if err != nil {
+		return err
+	}
+	x, y := GetBoardDrawingCoords(SquareToNative(to), PieceWidth, PieceHeight)
+	if err := slide.SlideTo(x, y, ReplaySlideTicks); err != nil {
+		return err
+	}
+	s.pieces[to] = actor
+	return nil
+}
+
+func squareDistance(a, b chess.Square) int {
+	files, ranks := a.File()-b.File(), a.Rank()-b.Rank()
+	return files*files + ranks*ranks
+}
+
+// keys are read once a frame, see UpdateInputInterface
+func (s *ReplayScene) UpdateInput() error {
+	if err := s.Scene.UpdateInput(); err != nil {
+		return err
+	}
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyRight):
+		return s.StepForward()
+	case inpututil.IsKeyJustPressed(ebiten.KeyLeft):
+		return s.StepBack()
+	case inpututil.IsKeyJustPressed(ebiten.KeyUp):
+		s.SelectVariation(-1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyDown):
+		s.SelectVariation(1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyHome):
+		return s.GoTo(s.GetGame().Root)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnd):
+		node := s.node
+		for len(node.Children) > 0 {
+			node = node.Children[0]
+		}
+		return s.GoTo(node)
+	case inpututil.IsKeyJustPressed(ebiten.KeyPageUp):
+		return s.SelectGame(s.gameIndex - 1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyPageDown):
+		return s.SelectGame(s.gameIndex + 1)
+	}
+	return nil
+}
+
+var nagSymbols = map[int]string{1: "!", 2: "?", 3: "!!", 4: "??", 5: "!?", 6: "?!"}
+
+// the move as it'd be written in a move list, with its number if it's white's or starts a line
+func replayMoveText(node *notation.PGNNode, withNumber bool) string {
+	text := node.SAN
+	for _, nag := range node.NAGs {
+		if symbol, ok := nagSymbols[nag]; ok {
+			text += symbol
+		} else {
+			text += " $" + strconv.Itoa(nag)
+		}
+	}
+	number, white := node.MoveNumber()
+	if white {
+		return strconv.Itoa(number) + ". " + text
+	}
+	if withNumber {
+		return strconv.Itoa(number) + "... " + text
+	}
+	return text
+}
+
+// breaks on spaces so no line is longer than width, unless a single word is
+func wrapText(text string, width int) []string {
+	lines, _ := wrapWords(strings.Fields(text), width)
+	return lines
+}
+
+// same as wrapText, for words that can have spaces in them. also says which line each word ended up on
+func wrapWords(words []string, width int) ([]string, []int) {
+	lines := make([]string, 0)
+	lineOf := make([]int, len(words))
+	line := ""
+	for i, word := range words {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+		lineOf[i] = len(lines)
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines, lineOf
+}
+
+func (s *ReplayScene) updateText() {
+	game := s.GetGame()
+	tag := func(name string) string {
+		if value, ok := game.Tag(name); ok {
+			return value
+		}
+		return "?"
+	}
+	header := fmt.Sprintf("%s vs %s  %s\n%s  %s", tag("White"), tag("Black"), game.Result, tag("Event"), tag("Date"))
+	if len(s.games) > 1 {
+		header += fmt.Sprintf("  (game %d of %d)", s.gameIndex+1, len(s.games))
+	}
+	s.header.SetText(header)
+
+	// the line we're on, from the start through to where it ends, with the current move in brackets
+	line := make([]*notation.PGNNode, 0)
+	for node := s.node; node.Parent != nil; node = node.Parent {
+		line = append([]*notation.PGNNode{node}, line...)
+	}
+	for node := s.node; len(node.Children) > 0; {
+		node = node.Children[0]
+		line = append(line, node)
+	}
+	words := make([]string, 0, len(line))
+	currentWord := -1
+	for i, node := range line {
+		word := replayMoveText(node, i == 0)
+		if node == s.node {
+			word = "[" + word + "]"
+			currentWord = i
+		}
+		words = append(words, word)
+	}
+	// only the few lines around the current move
+	lines, lineOf := wrapWords(words, ReplayTextWidthChars)
+	first := 0
+	if currentWord >= 0 {
+		first = int(math.Max(0, float64(lineOf[currentWord]-ReplayMoveListLines/2)))
+	}
+	last := int(math.Min(float64(len(lines)), float64(first+ReplayMoveListLines)))
+	s.moveList.SetText(strings.Join(lines[first:last], "\n"))
+
+	sidebar := make([]string, 0)
+	if s.node.Parent == nil {
+		sidebar = append(sidebar, "start of game")
+	}
+	for _, comment := range []string{s.node.CommentBefore, s.node.Comment} {
+		if comment != "" {
+			sidebar = append(sidebar, wrapText(comment, ReplayTextWidthChars)...)
+		}
+	}
+	if len(s.node.Children) > 1 {
+		if len(sidebar) > 0 {
+			sidebar = append(sidebar, "")
+		}
+		sidebar = append(sidebar, "variations (up/down to pick, right to play):")
+		for i, child := range s.node.Children {
+			marker := "  "
+			if i == s.choice {
+				marker = "> "
+			}
+			text := marker + replayMoveText(child, true)
+			if child.CommentBefore != "" {
+				text += "  {" + child.CommentBefore + "}"
+			}
+			if len(text) > ReplayTextWidthChars {
+				text = text[:ReplayTextWidthChars-3] + "..."
+			}
+			sidebar = append(sidebar, text)
+		}
+	}
+	s.sidebar.SetText(strings.Join(sidebar, "\n"))
+}
@@ -4,13 +4,15 @@ import (
 	"fmt"
 
 	"github.com/hajimehoshi/ebiten"
+	"github.com/val-is/bullet-hell-chess/collision"
 )
 
 type SceneGenerator func() (SceneInterface, error)
 
 const (
-	StartSceneId = "scene-start"
-	StopSceneId  = "scene-stop"
+	StartSceneId   = "scene-start"
+	StopSceneId    = "scene-stop"
+	ResultsSceneId = "scene-results"
 )
 
 type SceneMachine struct {
@@ -21,8 +23,9 @@ type SceneMachine struct {
 
 type SceneMachineInterface interface {
 	RunScene(sceneId string) error
-	Update() error
-	Draw(screen *ebiten.Image) error
+	UpdateInput() error
+	Update(sim SimClockInterface) error
+	Draw(screen *ebiten.Image, sim SimClockInterface) error
 	GetCurrentScene() SceneInterface
 	AddScene(sceneId string, generator SceneGenerator)
 }
@@ -35,25 +38,53 @@ func NewSceneMachine() (SceneMachineInterface, error) {
 	}, nil
 }
 
+// the new scene is generated before the old one exits, so generators can still look at the old one
 func (s *SceneMachine) RunScene(sceneId string) error {
-	s.activeSceneId = sceneId
-	scene, err := s.scenes[sceneId]()
+	generator, ok := s.scenes[sceneId]
+	if !ok {
+		return fmt.Errorf("scene %s has not been added", sceneId)
+	}
+	scene, err := generator()
 	if err != nil {
 		return err
 	}
+	if s.activeScene != nil {
+		if err := s.activeScene.Exit(); err != nil {
+			return err
+		}
+	}
+	s.activeSceneId = sceneId
 	s.activeScene = scene
-	return nil
+	return scene.Enter()
 }
 
-func (s *SceneMachine) Update() error {
-	return s.activeScene.Update()
+// scenes can't see the machine, so they ask for a transition and it happens once their update is done
+func (s *SceneMachine) UpdateInput() error {
+	if err := s.activeScene.UpdateInput(); err != nil {
+		return err
+	}
+	return s.runNextScene()
 }
 
-func (s *SceneMachine) Draw(screen *ebiten.Image) error {
+func (s *SceneMachine) Update(sim SimClockInterface) error {
+	if err := s.activeScene.Update(sim); err != nil {
+		return err
+	}
+	return s.runNextScene()
+}
+
+func (s *SceneMachine) runNextScene() error {
+	if nextSceneId := s.activeScene.GetNextScene(); nextSceneId != "" {
+		return s.RunScene(nextSceneId)
+	}
+	return nil
+}
+
+func (s *SceneMachine) Draw(screen *ebiten.Image, sim SimClockInterface) error {
 	for _, layer := range []RenderLayer{
 		RenderLayerBackground, RenderLayerForeground,
-		RenderLayerForegroundObject, RenderLayerUI} {
-		if err := s.activeScene.Draw(screen, layer); err != nil {
+		RenderLayerForegroundObject, RenderLayerBullet, RenderLayerUI} {
+		if err := s.activeScene.Draw(screen, layer, sim); err != nil {
 			return err
 		}
 	}
@@ -68,69 +99,313 @@ func (s *SceneMachine) AddScene(sceneId string, generator SceneGenerator) {
 	s.scenes[sceneId] = generator
 }
 
+// collision grid covers the screen plus a bit, anything further out is lumped into the edge cells
+const (
+	CollisionCellSize = 32.0
+	CollisionMargin   = 64.0
+)
+
 type Scene struct {
-	id     string
-	actors []ActorInterface
+	id          string
+	actors      []ActorInterface
+	nextSceneId string
+	// lookups so nothing has to scan every actor, both kept in step with actors
+	actorsById   map[string]ActorInterface
+	actorsByType map[string][]ActorInterface
+	// colliders registered last tick are queried while this tick's are being registered, then they swap
+	colliders     *collision.Grid
+	nextColliders *collision.Grid
+	// removals while looping over actors are held until the loop's done, so nothing shifts under it
+	iterating       bool
+	pendingRemovals []ActorInterface
+	removedTypes    map[string]bool
+	pools           map[string]*ActorPool
+	// pooled actors wait out two ticks after removal before being handed out again, since the collider
+	// grids can still point at them until then. [0] is from two ticks ago, [1] from the last one
+	recentlyRemoved [2][]ActorInterface
 }
 
 type SceneInterface interface {
-	Update() error
-	Draw(screen *ebiten.Image, renderLayer RenderLayer) error
+	UpdateInput() error
+	Update(sim SimClockInterface) error
+	Draw(screen *ebiten.Image, renderLayer RenderLayer, sim SimClockInterface) error
 	GetActorsType(actorType string) []ActorInterface
 	GetActorId(actorId string) (ActorInterface, error)
-	AddActor(actor ActorInterface)
+	AddActor(actor ActorInterface) error
+	RemoveActor(actorId string) error
+	Enter() error
+	Exit() error
+	GetPool(actorType string) *ActorPool
+	AddCollider(shape collision.Shape, actor ActorInterface, part uint64)
+	QueryColliders(shape collision.Shape, found func(actor ActorInterface, part uint64) (bool, error)) error
 	GetId() string
+	GetNextScene() string
+	SetNextScene(sceneId string)
 }
 
-func NewScene() (SceneInterface, error) {
-	s := Scene{
-		actors: make([]ActorInterface, 0),
+func newBaseScene() Scene {
+	newGrid := func() *collision.Grid {
+		return collision.NewGrid(-CollisionMargin, -CollisionMargin,
+			ScreenWidth+2*CollisionMargin, ScreenHeight+2*CollisionMargin, CollisionCellSize)
 	}
+	return Scene{
+		actors:          make([]ActorInterface, 0),
+		actorsById:      make(map[string]ActorInterface),
+		actorsByType:    make(map[string][]ActorInterface),
+		colliders:       newGrid(),
+		nextColliders:   newGrid(),
+		pendingRemovals: make([]ActorInterface, 0),
+		removedTypes:    make(map[string]bool),
+		pools:           make(map[string]*ActorPool),
+		recentlyRemoved: [2][]ActorInterface{
+			make([]ActorInterface, 0), make([]ActorInterface, 0),
+		},
+	}
+}
+
+func NewScene() (SceneInterface, error) {
+	s := newBaseScene()
 
 	return &s, nil
 }
 
-func (s *Scene) Update() error {
-	for k := range s.actors {
-		if err := s.actors[k].Update(); err != nil {
+// actors removed mid-update (e.g. captures) don't get updated after that, but stay in the slice until the end
+func (s *Scene) Update(sim SimClockInterface) error {
+	s.nextColliders.Clear()
+	for _, actor := range s.recentlyRemoved[0] {
+		s.pools[actor.GetActorType()].Put(actor)
+	}
+	s.recentlyRemoved[0], s.recentlyRemoved[1] = s.recentlyRemoved[1], s.recentlyRemoved[0][:0]
+
+	s.iterating = true
+	err := s.updateActors(sim)
+	s.iterating = false
+	s.flushRemovals()
+	if err != nil {
+		return err
+	}
+	s.colliders, s.nextColliders = s.nextColliders, s.colliders
+	return nil
+}
+
+// anything added during the loop is appended past where the range stops, so it waits for next tick
+func (s *Scene) updateActors(sim SimClockInterface) error {
+	for _, actor := range s.actors {
+		if !s.isPresent(actor) {
+			continue
+		}
+		if err := actor.Update(sim); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (s *Scene) Draw(screen *ebiten.Image, renderLayer RenderLayer) error {
-	for k := range s.actors {
-		if err := s.actors[k].Draw(screen, renderLayer); err != nil {
+// once a frame, before the ticks. removals wait for the end the same as in Update, since input can capture pieces
+func (s *Scene) UpdateInput() error {
+	s.iterating = true
+	err := s.updateActorInput()
+	s.iterating = false
+	s.flushRemovals()
+	return err
+}
+
+func (s *Scene) updateActorInput() error {
+	for _, actor := range s.actors {
+		if !s.isPresent(actor) {
+			continue
+		}
+		hook, ok := actor.(UpdateInputInterface)
+		if !ok {
+			continue
+		}
+		if err := hook.UpdateInput(); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+func (s *Scene) Draw(screen *ebiten.Image, renderLayer RenderLayer, sim SimClockInterface) error {
+	for _, actor := range s.actors {
+		if err := actor.Draw(screen, renderLayer, sim); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// the returned slice is shared and shifts when actors are removed, copy it first to remove while looping
+// outside of an update. actors of the type removed earlier in the same update are left out, in a copy
 func (s *Scene) GetActorsType(actorType string) []ActorInterface {
-	actorsFound := make([]ActorInterface, 0)
-	for k := range s.actors {
-		if s.actors[k].GetActorType() == actorType {
-			actorsFound = append(actorsFound, s.actors[k])
+	if !s.removedTypes[actorType] {
+		return s.actorsByType[actorType]
+	}
+	actors := s.actorsByType[actorType]
+	kept := make([]ActorInterface, 0, len(actors))
+	for _, actor := range actors {
+		if s.isPresent(actor) {
+			kept = append(kept, actor)
 		}
 	}
-	return actorsFound
+	return kept
 }
 
 func (s *Scene) GetActorId(actorId string) (ActorInterface, error) {
-	for k := range s.actors {
-		if s.actors[k].GetId() == actorId {
-			return s.actors[k], nil
-		}
+	if actor, ok := s.actorsById[actorId]; ok {
+		return actor, nil
 	}
 	return nil, fmt.Errorf("actor %s not found in scene %s", actorId, s.id)
 }
 
-func (s *Scene) AddActor(actor ActorInterface) {
+func (s *Scene) AddActor(actor ActorInterface) error {
+	if _, exists := s.actorsById[actor.GetId()]; exists {
+		return fmt.Errorf("actor %s is already in scene %s", actor.GetId(), s.id)
+	}
 	s.actors = append(s.actors, actor)
+	s.actorsById[actor.GetId()] = actor
+	s.actorsByType[actor.GetActorType()] = append(s.actorsByType[actor.GetActorType()], actor)
+	if hook, ok := actor.(OnAddInterface); ok {
+		return hook.OnAdd()
+	}
+	return nil
+}
+
+// the actor is gone from lookups straight away, but during an update it's only taken out of the slices
+// once the update is done. if its type has a pool it goes back in there once nothing can still be holding on to it
+func (s *Scene) RemoveActor(actorId string) error {
+	actor, ok := s.actorsById[actorId]
+	if !ok {
+		return fmt.Errorf("actor %s not found in scene %s", actorId, s.id)
+	}
+	delete(s.actorsById, actorId)
+	s.pendingRemovals = append(s.pendingRemovals, actor)
+	s.removedTypes[actor.GetActorType()] = true
+	if _, pooled := s.pools[actor.GetActorType()]; pooled {
+		s.recentlyRemoved[1] = append(s.recentlyRemoved[1], actor)
+	}
+	if !s.iterating {
+		s.flushRemovals()
+	}
+	if hook, ok := actor.(OnRemoveInterface); ok {
+		return hook.OnRemove()
+	}
+	return nil
+}
+
+// one pass over each affected slice however many actors went, keeping whatever's still in the id lookup
+func (s *Scene) flushRemovals() {
+	if len(s.pendingRemovals) == 0 {
+		return
+	}
+	s.actors = s.withoutRemoved(s.actors)
+	for actorType := range s.removedTypes {
+		s.actorsByType[actorType] = s.withoutRemoved(s.actorsByType[actorType])
+		delete(s.removedTypes, actorType)
+	}
+	for k := range s.pendingRemovals {
+		s.pendingRemovals[k] = nil
+	}
+	s.pendingRemovals = s.pendingRemovals[:0]
+}
+
+// still in the scene, and not just something else added under the same id since it was removed
+func (s *Scene) isPresent(actor ActorInterface) bool {
+	present, ok := s.actorsById[actor.GetId()]
+	return ok && present == actor
+}
+
+func (s *Scene) withoutRemoved(actors []ActorInterface) []ActorInterface {
+	kept := actors[:0]
+	for _, actor := range actors {
+		if s.isPresent(actor) {
+			kept = append(kept, actor)
+		}
+	}
+	for k := len(kept); k < len(actors); k++ {
+		actors[k] = nil
+	}
+	return kept
+}
+
+func (s *Scene) Enter() error {
+	s.iterating = true
+	defer func() {
+		s.iterating = false
+		s.flushRemovals()
+	}()
+	for _, actor := range s.actors {
+		if hook, ok := actor.(OnSceneEnterInterface); ok {
+			if err := hook.OnSceneEnter(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Scene) Exit() error {
+	s.iterating = true
+	defer func() {
+		s.iterating = false
+		s.flushRemovals()
+	}()
+	for _, actor := range s.actors {
+		if hook, ok := actor.(OnSceneExitInterface); ok {
+			if err := hook.OnSceneExit(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// pool for recycling actors of a type, made the first time it's asked for
+func (s *Scene) GetPool(actorType string) *ActorPool {
+	pool, ok := s.pools[actorType]
+	if !ok {
+		pool = NewActorPool()
+		s.pools[actorType] = pool
+	}
+	return pool
+}
+
+// registers a shape for this tick, it can be found from next tick on. the shape's copied so it can be reused
+// part is handed back by QueryColliders, for actors with more than one collider (e.g. the bullet field's bullets)
+func (s *Scene) AddCollider(shape collision.Shape, actor ActorInterface, part uint64) {
+	s.nextColliders.Insert(shape, actor, part)
+}
+
+// everything registered last tick that touches the shape and is still in the scene, stops when found returns false
+func (s *Scene) QueryColliders(shape collision.Shape, found func(actor ActorInterface, part uint64) (bool, error)) error {
+	var err error
+	s.colliders.Query(shape, func(entry collision.Entry) bool {
+		actor, ok := entry.Value.(ActorInterface)
+		if !ok {
+			err = fmt.Errorf("collider %d in scene %s belongs to a %T, not an actor", entry.ID, s.id, entry.Value)
+			return false
+		}
+		if !s.isPresent(actor) {
+			return true
+		}
+		keepGoing, foundErr := found(actor, entry.ID)
+		if foundErr != nil {
+			err = foundErr
+			return false
+		}
+		return keepGoing
+	})
+	return err
 }
 
 func (s *Scene) GetId() string {
 	return s.id
 }
+
+func (s *Scene) GetNextScene() string {
+	return s.nextSceneId
+}
+
+func (s *Scene) SetNextScene(sceneId string) {
+	s.nextSceneId = sceneId
+}
@@ -0,0 +1,271 @@
+package engine
+
+import (
+	"fmt"
+	"testing"
+)
+
+const actorTypeTest = "actor-test"
+
+// counts every hook it gets, and runs update (if set) each tick
+type testComponent struct {
+	Component
+	update                 func() error
+	updates, adds, removes int
+	resets, enters, exits  int
+	removeOrder, addOrder  int
+}
+
+// shared between the components of one test so hooks can be put in order
+var hookCounter int
+
+func (c *testComponent) Update(sim SimClockInterface) error {
+	c.updates++
+	if c.update != nil {
+		return c.update()
+	}
+	return nil
+}
+
+func (c *testComponent) OnAdd() error {
+	hookCounter++
+	c.adds++
+	c.addOrder = hookCounter
+	return nil
+}
+
+func (c *testComponent) OnRemove() error {
+	hookCounter++
+	c.removes++
+	c.removeOrder = hookCounter
+	return nil
+}
+
+func (c *testComponent) Reset() {
+	c.resets++
+}
+
+func (c *testComponent) OnSceneEnter() error {
+	c.enters++
+	return nil
+}
+
+func (c *testComponent) OnSceneExit() error {
+	c.exits++
+	return nil
+}
+
+func newTestActor(scene SceneInterface, id string) (*Actor, *testComponent) {
+	actor := &Actor{parentScene: scene, actorType: actorTypeTest, id: id}
+	component := &testComponent{Component: Component{actor, ComponentTypeBasic}}
+	actor.components = []ComponentInterface{component}
+	return actor, component
+}
+
+func newTestScene(t *testing.T, ids ...string) (SceneInterface, []*testComponent) {
+	scene, err := NewScene()
+	if err != nil {
+		t.Fatal(err)
+	}
+	components := make([]*testComponent, len(ids))
+	for k, id := range ids {
+		actor, component := newTestActor(scene, id)
+		if err := scene.AddActor(actor); err != nil {
+			t.Fatal(err)
+		}
+		components[k] = component
+	}
+	return scene, components
+}
+
+func actorIds(actors []ActorInterface) string {
+	ids := ""
+	for k, actor := range actors {
+		if k > 0 {
+			ids += " "
+		}
+		ids += actor.GetId()
+	}
+	return ids
+}
+
+// an actor removed part way through a tick isn't updated after that, and no lookup finds it
+func TestRemoveDuringUpdate(t *testing.T) {
+	scene, components := newTestScene(t, "a", "b", "c")
+	sim := newTestSimClock(t)
+
+	components[0].update = func() error {
+		if components[0].updates > 1 {
+			return nil
+		}
+		if err := scene.RemoveActor("b"); err != nil {
+			return err
+		}
+		if ids := actorIds(scene.GetActorsType(actorTypeTest)); ids != "a c" {
+			return fmt.Errorf("actors of the type straight after removing b are %q, want \"a c\"", ids)
+		}
+		if _, err := scene.GetActorId("b"); err == nil {
+			return fmt.Errorf("b can still be looked up after it was removed")
+		}
+		return nil
+	}
+	if err := scene.Update(sim); err != nil {
+		t.Fatal(err)
+	}
+	if components[1].updates != 0 {
+		t.Errorf("b was updated %d times after being removed earlier in the tick", components[1].updates)
+	}
+	if components[2].updates != 1 {
+		t.Errorf("c was updated %d times, want 1", components[2].updates)
+	}
+	if ids := actorIds(scene.GetActorsType(actorTypeTest)); ids != "a c" {
+		t.Errorf("actors of the type after the tick are %q, want \"a c\"", ids)
+	}
+
+	if err := scene.Update(sim); err != nil {
+		t.Fatal(err)
+	}
+	if components[0].updates != 2 || components[1].updates != 0 || components[2].updates != 2 {
+		t.Errorf("updates after two ticks are %d %d %d, want 2 0 2",
+			components[0].updates, components[1].updates, components[2].updates)
+	}
+}
+
+// taking a piece off a square and putting another there in the same tick (SyncPieceActors) only ever
+// finds the new one
+func TestReplaceDuringUpdate(t *testing.T) {
+	scene, components := newTestScene(t, "a", "b")
+	sim := newTestSimClock(t)
+
+	var added *testComponent
+	components[0].update = func() error {
+		if added != nil {
+			return nil
+		}
+		if err := scene.RemoveActor("b"); err != nil {
+			return err
+		}
+		actor, component := newTestActor(scene, "b")
+		if err := scene.AddActor(actor); err != nil {
+			return err
+		}
+		added = component
+		found, err := scene.GetActorId("b")
+		if err != nil {
+			return err
+		}
+		if found != ActorInterface(actor) {
+			return fmt.Errorf("looking up b found the removed actor")
+		}
+		for _, other := range scene.GetActorsType(actorTypeTest) {
+			if other.GetId() == "b" && other != ActorInterface(actor) {
+				return fmt.Errorf("actors of the type still have the removed b")
+			}
+		}
+		return nil
+	}
+	if err := scene.Update(sim); err != nil {
+		t.Fatal(err)
+	}
+	// added during the tick, so it waits for the next one
+	if added.updates != 0 || components[1].updates != 0 {
+		t.Errorf("new b updated %d times, old b %d, want neither", added.updates, components[1].updates)
+	}
+	if ids := actorIds(scene.GetActorsType(actorTypeTest)); ids != "a b" {
+		t.Errorf("actors of the type after the tick are %q, want \"a b\"", ids)
+	}
+	if err := scene.Update(sim); err != nil {
+		t.Fatal(err)
+	}
+	if added.updates != 1 || components[1].updates != 0 {
+		t.Errorf("after another tick new b updated %d times, old b %d, want 1 and 0", added.updates, components[1].updates)
+	}
+}
+
+func TestLifecycleHooks(t *testing.T) {
+	hookCounter = 0
+	scene, components := newTestScene(t, "a", "b")
+	a, b := components[0], components[1]
+	if a.adds != 1 || b.adds != 1 || a.addOrder != 1 || b.addOrder != 2 {
+		t.Errorf("OnAdd called %d and %d times, in order %d %d", a.adds, b.adds, a.addOrder, b.addOrder)
+	}
+
+	duplicate, _ := newTestActor(scene, "a")
+	if err := scene.AddActor(duplicate); err == nil {
+		t.Error("added a second actor with the id a")
+	}
+	if a.adds != 1 {
+		t.Errorf("OnAdd called %d times on a after adding a duplicate", a.adds)
+	}
+
+	if err := scene.Enter(); err != nil {
+		t.Fatal(err)
+	}
+	if err := scene.RemoveActor("b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := scene.RemoveActor("b"); err == nil {
+		t.Error("removed b twice")
+	}
+	if b.removes != 1 || b.removeOrder != 3 {
+		t.Errorf("OnRemove called %d times on b, at %d", b.removes, b.removeOrder)
+	}
+	if err := scene.Exit(); err != nil {
+		t.Fatal(err)
+	}
+	if a.enters != 1 || a.exits != 1 || b.enters != 1 || b.exits != 0 {
+		t.Errorf("enter/exit a %d %d, b %d %d, want 1 1 and 1 0", a.enters, a.exits, b.enters, b.exits)
+	}
+	if a.removes != 0 {
+		t.Errorf("OnRemove called on a, which is still in the scene")
+	}
+}
+
+// removed actors of a pooled type go back in the pool reset, but only once the collider grids they
+// were in have both been cleared
+func TestPooledActorReset(t *testing.T) {
+	scene, components := newTestScene(t, "a", "b")
+	pool := scene.GetPool(actorTypeTest)
+	sim := newTestSimClock(t)
+
+	components[0].update = func() error {
+		if components[0].updates == 1 {
+			return scene.RemoveActor("b")
+		}
+		return nil
+	}
+	if err := scene.Update(sim); err != nil {
+		t.Fatal(err)
+	}
+	if components[1].removes != 1 {
+		t.Fatalf("OnRemove called %d times on b", components[1].removes)
+	}
+	for tick := 2; tick <= 3; tick++ {
+		if pool.Len() != 0 || components[1].resets != 0 {
+			t.Fatalf("b was pooled before tick %d", tick)
+		}
+		if err := scene.Update(sim); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if pool.Len() != 1 || components[1].resets != 1 {
+		t.Fatalf("pool has %d actors and b was reset %d times after three ticks, want 1 and 1", pool.Len(), components[1].resets)
+	}
+
+	actor, ok := pool.Get()
+	if !ok || actor.GetId() != "b" {
+		t.Fatalf("got %v %v out of the pool, want b", actor, ok)
+	}
+	if err := scene.AddActor(actor); err != nil {
+		t.Fatal(err)
+	}
+	if components[1].adds != 2 {
+		t.Errorf("OnAdd called %d times on b after it came back, want 2", components[1].adds)
+	}
+	if err := scene.Update(sim); err != nil {
+		t.Fatal(err)
+	}
+	if pool.Len() != 0 || components[1].resets != 1 {
+		t.Errorf("b went back in the pool while it was in the scene")
+	}
+}
@@ -0,0 +1,136 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+	"github.com/val-is/bullet-hell-chess/notation"
+)
+
+type GameMode string
+
+const (
+	// regular chess, sides alternate
+	GameModeTurns GameMode = "turns"
+	// either side can move whenever, but a piece has to wait out a cooldown after it moves
+	GameModeRealtime GameMode = "realtime"
+)
+
+func ParseGameMode(mode string) (GameMode, error) {
+	switch GameMode(mode) {
+	case GameModeTurns, GameModeRealtime:
+		return GameMode(mode), nil
+	}
+	return "", fmt.Errorf("unknown game mode %s", mode)
+}
+
+type InputMode string
+
+const (
+	// pick a piece up and drop it on its destination
+	InputModeDrag InputMode = "drag"
+	// click a piece to select it, then click where it should go
+	InputModeClick InputMode = "click"
+)
+
+func ParseInputMode(mode string) (InputMode, error) {
+	switch InputMode(mode) {
+	case InputModeDrag, InputModeClick:
+		return InputMode(mode), nil
+	}
+	return "", fmt.Errorf("unknown input mode %s", mode)
+}
+
+// what happens on the board when a player gets hit by a bullet
+type HitPenalty string
+
+const (
+	HitPenaltyNone HitPenalty = "none"
+	// time comes straight off the player's clock
+	HitPenaltyClockTime HitPenalty = "clock"
+	// the player's turn is skipped (turn mode only)
+	HitPenaltyForfeitTurn HitPenalty = "forfeit"
+	// one of the player's pieces, never the king, is taken off at random
+	HitPenaltyLosePiece HitPenalty = "piece"
+)
+
+func ParseHitPenalty(penalty string) (HitPenalty, error) {
+	switch HitPenalty(penalty) {
+	case HitPenaltyNone, HitPenaltyClockTime, HitPenaltyForfeitTurn, HitPenaltyLosePiece:
+		return HitPenalty(penalty), nil
+	}
+	return "", fmt.Errorf("unknown hit penalty %s", penalty)
+}
+
+// what happens with moves that get taken back
+type TakebackMode string
+
+const (
+	// moves stand once they're made
+	TakebackNone TakebackMode = "none"
+	// ctrl+z and ctrl+y undo and redo straight away, for playing both sides or trying lines out
+	TakebackFree TakebackMode = "free"
+	// ctrl+z asks for the last move back, and the other player has to say yes (y) or no (n)
+	TakebackAsk TakebackMode = "ask"
+)
+
+func ParseTakebackMode(mode string) (TakebackMode, error) {
+	switch TakebackMode(mode) {
+	case TakebackNone, TakebackFree, TakebackAsk:
+		return TakebackMode(mode), nil
+	}
+	return "", fmt.Errorf("unknown takeback mode %s", mode)
+}
+
+// everything about a game that can be picked before it starts
+type GameSettings struct {
+	Mode               GameMode
+	PieceCooldownTicks int
+	InputMode          InputMode
+	TimeControl        chess.TimeControl
+
+	// 0 lives means getting hit never loses the game outright
+	Lives                int
+	InvulnerabilityTicks int
+	CursorHitboxRadius   float64
+	HitPenalty           HitPenalty
+	HitClockPenalty      time.Duration
+	// which moves fire which bullet patterns, empty for no bullets at all
+	TriggerFile string
+	// where the game starts from
+	FEN       string
+	Takebacks TakebackMode
+
+	// for the pgn
+	WhiteName string
+	BlackName string
+	// where finished games are saved as pgn, empty to not save them
+	PGNDir string
+	// pgn to step through instead of playing, if there is one
+	ReplayFile string
+}
+
+func DefaultGameSettings() GameSettings {
+	return GameSettings{
+		Mode:               GameModeTurns,
+		PieceCooldownTicks: 180,
+		InputMode:          InputModeDrag,
+		TimeControl: chess.TimeControl{
+			Base:      time.Minute,
+			Increment: 0,
+			Delay:     chess.DelayFischer,
+		},
+		Lives:                5,
+		InvulnerabilityTicks: 90,
+		CursorHitboxRadius:   3,
+		HitPenalty:           HitPenaltyClockTime,
+		HitClockPenalty:      5 * time.Second,
+		TriggerFile:          DefaultTriggerFile,
+		FEN:                  notation.StartingFEN,
+		Takebacks:            TakebackAsk,
+		WhiteName:            "?",
+		BlackName:            "?",
+		PGNDir:               DefaultPGNDir,
+	}
+}
@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"math"
+	"time"
+)
+
+// the sim always steps by the same amount of (sim) time, however often ebiten calls Update
+// and whatever the time scale is. slow motion just means fewer steps per real second,
+// and Draw interpolates between the last two steps so movement still looks smooth
+const TicksPerSecond = 60
+
+const SimStep = time.Second / TicksPerSecond
+
+// more than this many steps owed in one go and the rest are dropped, rather than the game spiralling
+// trying to catch up after a hitch
+const MaxStepsPerUpdate = 4
+
+type SimClock struct {
+	tick      int
+	timeScale float64
+	paused    bool
+	// scaled time not yet stepped through
+	accumulator time.Duration
+	// when the last Advance happened, so frames drawn between updates can tell how far along they are
+	lastAdvance time.Time
+	alpha       float64
+}
+
+type SimClockInterface interface {
+	// ticks stepped so far
+	GetTick() int
+	// sim time per tick, always SimStep. speeds and timers given per tick are per this much time
+	GetDT() time.Duration
+	GetTimeScale() float64
+	SetTimeScale(scale float64)
+	IsPaused() bool
+	SetPaused(paused bool)
+	// how far between the previous tick and the latest one to draw things, 0 to 1
+	GetAlpha() float64
+
+	// real time has passed, returns how many ticks should be stepped now
+	Advance(elapsed time.Duration, now time.Time) int
+	// one tick's been stepped
+	Step()
+	// works out the alpha for a frame drawn at now
+	Interpolate(now time.Time)
+}
+
+func NewSimClock() (SimClockInterface, error) {
+	return &SimClock{
+		timeScale: 1,
+		alpha:     1,
+	}, nil
+}
+
+func (c *SimClock) GetTick() int {
+	return c.tick
+}
+
+func (c *SimClock) GetDT() time.Duration {
+	return SimStep
+}
+
+func (c *SimClock) GetTimeScale() float64 {
+	return c.timeScale
+}
+
+func (c *SimClock) SetTimeScale(scale float64) {
+	c.timeScale = math.Max(scale, 0)
+}
+
+func (c *SimClock) IsPaused() bool {
+	return c.paused
+}
+
+func (c *SimClock) SetPaused(paused bool) {
+	c.paused = paused
+}
+
+func (c *SimClock) GetAlpha() float64 {
+	return c.alpha
+}
+
+func (c *SimClock) Advance(elapsed time.Duration, now time.Time) int {
+	c.lastAdvance = now
+	if c.paused {
+		return 0
+	}
+	c.accumulator += time.Duration(float64(elapsed) * c.timeScale)
+	steps := int(c.accumulator / SimStep)
+	if steps > MaxStepsPerUpdate {
+		steps = MaxStepsPerUpdate
+		c.accumulator = SimStep * MaxStepsPerUpdate
+	}
+	return steps
+}
+
+// can be called with less than a tick owed (e.g. stepping a tick at a time while paused), that doesn't
+// eat into what is owed
+func (c *SimClock) Step() {
+	c.tick++
+	if c.accumulator >= SimStep {
+		c.accumulator -= SimStep
+	}
+}
+
+func (c *SimClock) Interpolate(now time.Time) {
+	pending := c.accumulator
+	if !c.paused && !c.lastAdvance.IsZero() {
+		pending += time.Duration(float64(now.Sub(c.lastAdvance)) * c.timeScale)
+	}
+	c.alpha = math.Min(math.Max(float64(pending)/float64(SimStep), 0), 1)
+}
+
+// somewhere between where something was last tick and where it is now
+func Lerp(from, to, alpha float64) float64 {
+	return from + (to-from)*alpha
+}
@@ -0,0 +1,158 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestSimClock(t *testing.T) SimClockInterface {
+	sim, err := NewSimClock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sim
+}
+
+// steps however many ticks Advance asks for, like Game.Update does
+func advance(sim SimClockInterface, elapsed time.Duration, now time.Time) int {
+	steps := sim.Advance(elapsed, now)
+	for i := 0; i < steps; i++ {
+		sim.Step()
+	}
+	return steps
+}
+
+func TestSimClockSteps(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		scale   float64
+		elapsed []time.Duration
+		steps   []int
+	}{
+		{"one step per step", 1, []time.Duration{SimStep, SimStep, SimStep}, []int{1, 1, 1}},
+		{"several at once", 1, []time.Duration{3 * SimStep}, []int{3}},
+		{"remainder carries over", 1, []time.Duration{SimStep / 2, SimStep / 2, SimStep * 3 / 2, SimStep / 2}, []int{0, 1, 1, 1}},
+		{"half speed", 0.5, []time.Duration{SimStep, SimStep, 4 * SimStep}, []int{0, 1, 2}},
+		{"double speed", 2, []time.Duration{SimStep, SimStep / 2}, []int{2, 1}},
+		{"stopped", 0, []time.Duration{10 * SimStep}, []int{0}},
+	} {
+		sim := newTestSimClock(t)
+		sim.SetTimeScale(tc.scale)
+		now := time.Unix(0, 0)
+		total := 0
+		for i, elapsed := range tc.elapsed {
+			now = now.Add(elapsed)
+			if steps := advance(sim, elapsed, now); steps != tc.steps[i] {
+				t.Errorf("%s: advance %d by %v stepped %d, want %d", tc.name, i, elapsed, steps, tc.steps[i])
+			}
+			total += tc.steps[i]
+		}
+		if sim.GetTick() != total {
+			t.Errorf("%s: at tick %d, want %d", tc.name, sim.GetTick(), total)
+		}
+		if sim.GetDT() != SimStep {
+			t.Errorf("%s: dt %v, want %v", tc.name, sim.GetDT(), SimStep)
+		}
+	}
+
+	sim := newTestSimClock(t)
+	sim.SetTimeScale(-1)
+	if sim.GetTimeScale() != 0 {
+		t.Errorf("time scale %v after setting it negative, want 0", sim.GetTimeScale())
+	}
+}
+
+// a long hitch is only caught up on as far as MaxStepsPerUpdate, the rest is dropped
+func TestSimClockClamp(t *testing.T) {
+	sim := newTestSimClock(t)
+	now := time.Unix(0, 0).Add(time.Second)
+	if steps := advance(sim, time.Second, now); steps != MaxStepsPerUpdate {
+		t.Fatalf("a second's hitch stepped %d, want %d", steps, MaxStepsPerUpdate)
+	}
+	if steps := advance(sim, 0, now); steps != 0 {
+		t.Errorf("stepped %d more after the hitch, want the rest dropped", steps)
+	}
+	if steps := advance(sim, SimStep, now.Add(SimStep)); steps != 1 {
+		t.Errorf("stepped %d the frame after, want 1", steps)
+	}
+}
+
+func TestSimClockPause(t *testing.T) {
+	sim := newTestSimClock(t)
+	now := time.Unix(0, 0)
+	now = now.Add(SimStep / 2)
+	advance(sim, SimStep/2, now)
+
+	sim.SetPaused(true)
+	if !sim.IsPaused() {
+		t.Fatal("not paused after pausing")
+	}
+	for i := 0; i < 10; i++ {
+		now = now.Add(SimStep)
+		if steps := advance(sim, SimStep, now); steps != 0 {
+			t.Fatalf("stepped %d while paused", steps)
+		}
+	}
+
+	// stepping by hand while paused moves one tick, and doesn't take away from the time already owed
+	sim.Step()
+	if sim.GetTick() != 1 {
+		t.Errorf("at tick %d after a step while paused, want 1", sim.GetTick())
+	}
+	sim.Step()
+	if sim.GetTick() != 2 {
+		t.Errorf("at tick %d after two steps while paused, want 2", sim.GetTick())
+	}
+
+	// the time spent paused isn't owed afterwards
+	sim.SetPaused(false)
+	now = now.Add(SimStep / 2)
+	if steps := advance(sim, SimStep/2, now); steps != 1 {
+		t.Errorf("stepped %d after unpausing, want 1", steps)
+	}
+	if sim.GetTick() != 3 {
+		t.Errorf("at tick %d after unpausing, want 3", sim.GetTick())
+	}
+}
+
+func TestSimClockAlpha(t *testing.T) {
+	sim := newTestSimClock(t)
+	now := time.Unix(0, 0)
+	for _, elapsed := range []time.Duration{
+		SimStep, SimStep / 3, SimStep / 3, SimStep / 3, SimStep * 5 / 2, time.Millisecond, SimStep - 1, 10 * SimStep,
+	} {
+		now = now.Add(elapsed)
+		advance(sim, elapsed, now)
+		sim.Interpolate(now)
+		if alpha := sim.GetAlpha(); alpha < 0 || alpha >= 1 {
+			t.Errorf("alpha %v straight after stepping by %v, want it in [0, 1)", alpha, elapsed)
+		}
+	}
+
+	// frames drawn between updates move along, but never past the latest tick
+	sim = newTestSimClock(t)
+	now = time.Unix(0, 0).Add(SimStep)
+	advance(sim, SimStep, now)
+	sim.Interpolate(now)
+	previous := sim.GetAlpha()
+	for _, later := range []time.Duration{SimStep / 4, SimStep / 2, SimStep * 3 / 4, 2 * SimStep} {
+		sim.Interpolate(now.Add(later))
+		alpha := sim.GetAlpha()
+		if alpha < previous || alpha > 1 {
+			t.Errorf("alpha %v %v after the update, after %v", alpha, later, previous)
+		}
+		previous = alpha
+	}
+	if previous != 1 {
+		t.Errorf("alpha %v two ticks after the update, want it held at 1", previous)
+	}
+
+	// paused, the frame stays where it was
+	sim.SetPaused(true)
+	sim.Interpolate(now)
+	paused := sim.GetAlpha()
+	sim.Interpolate(now.Add(time.Hour))
+	if sim.GetAlpha() != paused {
+		t.Errorf("alpha moved from %v to %v while paused", paused, sim.GetAlpha())
+	}
+}
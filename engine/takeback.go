@@ -0,0 +1,111 @@
+package engine
+
+import (
+	"log"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/inpututil"
+	"github.com/val-is/bullet-hell-chess/chess"
+)
+
+// nothing gets taken back once the game's over, or while a pawn is waiting on its promotion
+func (s *ChessScene) canTakeBack() bool {
+	return !s.outcome.IsOver() && len(s.GetActorsType(ActorTypePromotionOption)) == 0
+}
+
+// takes back the last move, or the last penalty (a skipped turn or a piece taken off), clocks included
+func (s *ChessScene) Undo() error {
+	if !s.canTakeBack() || !s.game.Undo() {
+		return nil
+	}
+	return s.afterTakeback()
+}
+
+func (s *ChessScene) Redo() error {
+	if !s.canTakeBack() {
+		return nil
+	}
+	redone, err := s.game.Redo()
+	if err != nil || !redone {
+		return err
+	}
+	return s.afterTakeback()
+}
+
+// the pieces on the board don't know the game went backwards, so they're lined up with it again
+func (s *ChessScene) afterTakeback() error {
+	s.ClearSelection()
+	s.takebackAsked = false
+	s.SetOutcome(s.game.Outcome())
+	return SyncPieceActors(s, s.game.Position, PieceAssetDir)
+}
+
+// whoever made the last move asks for it back. penalties can't be asked back, and neither can a move
+// that isn't the last thing that happened
+func (s *ChessScene) RequestTakeback() error {
+	entry, ok := s.game.UndoEntry()
+	if !s.canTakeBack() || !ok || entry.Kind != chess.RecordMove {
+		return nil
+	}
+	s.takebackFrom = ColorToBoardSide(s.game.Position.PieceAt(entry.Move.To).Color)
+	s.takebackRecord = len(s.game.Record)
+	s.takebackAsked = true
+	log.Printf("%s asks to take back %s", s.takebackFrom, entry.Move)
+	return nil
+}
+
+// the side asking, if there's a request waiting. anything happening on the board since it was made
+// (another move, a penalty) means it doesn't count any more
+func (s *ChessScene) GetTakebackRequest() (BoardSide, bool) {
+	waiting := s.takebackAsked && s.takebackRecord == len(s.game.Record) && s.canTakeBack()
+	return s.takebackFrom, waiting
+}
+
+// the other side's answer to the waiting request
+func (s *ChessScene) AnswerTakeback(accept bool) error {
+	side, waiting := s.GetTakebackRequest()
+	if !waiting {
+		return nil
+	}
+	s.takebackAsked = false
+	if !accept {
+		log.Printf("%s's takeback was turned down", side)
+		return nil
+	}
+	return s.Undo()
+}
+
+func (s *ChessScene) UpdateInput() error {
+	if err := s.Scene.UpdateInput(); err != nil {
+		return err
+	}
+	return s.updateTakebackKeys()
+}
+
+// ctrl+z and ctrl+y (or ctrl+shift+z) with free takebacks, ctrl+z then y or n when the other side has to agree
+func (s *ChessScene) updateTakebackKeys() error {
+	ctrl := ebiten.IsKeyPressed(ebiten.KeyControl)
+	undo := ctrl && inpututil.IsKeyJustPressed(ebiten.KeyZ) && !ebiten.IsKeyPressed(ebiten.KeyShift)
+	switch s.settings.Takebacks {
+	case TakebackFree:
+		redo := ctrl && (inpututil.IsKeyJustPressed(ebiten.KeyY) ||
+			inpututil.IsKeyJustPressed(ebiten.KeyZ) && ebiten.IsKeyPressed(ebiten.KeyShift))
+		switch {
+		case undo:
+			return s.Undo()
+		case redo:
+			return s.Redo()
+		}
+	case TakebackAsk:
+		_, waiting := s.GetTakebackRequest()
+		switch {
+		case undo:
+			return s.RequestTakeback()
+		case waiting && inpututil.IsKeyJustPressed(ebiten.KeyY):
+			return s.AnswerTakeback(true)
+		case waiting && inpututil.IsKeyJustPressed(ebiten.KeyN):
+			return s.AnswerTakeback(false)
+		}
+	}
+	return nil
+}
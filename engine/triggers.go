@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+	"github.com/val-is/bullet-hell-chess/pattern"
+)
+
+const DefaultTriggerFile = "assets/triggers.json"
+
+// which pattern (by name) goes off for what happens on the board, anything left out fires nothing
+type PatternTriggers struct {
+	Move    map[ChessPiece]string `json:"move"`
+	Capture string                `json:"capture"`
+	Check   string                `json:"check"`
+}
+
+func LoadPatternTriggers(filename string) (PatternTriggers, error) {
+	triggers := PatternTriggers{}
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return triggers, err
+	}
+	if err := json.Unmarshal(data, &triggers); err != nil {
+		return triggers, fmt.Errorf("%s: %s", filename, err)
+	}
+	return triggers, nil
+}
+
+// makes sure every pattern mentioned has been loaded
+func (t PatternTriggers) Validate(patterns map[string]pattern.File) error {
+	check := func(trigger, name string) error {
+		if _, ok := patterns[name]; name != "" && !ok {
+			return fmt.Errorf("%s trigger uses pattern %s, which doesn't exist", trigger, name)
+		}
+		return nil
+	}
+	for piece, name := range t.Move {
+		if ChessPieceToType(piece) == chess.NoPieceType {
+			return fmt.Errorf("move trigger for unknown piece %s", piece)
+		}
+		if err := check(string(piece)+" move", name); err != nil {
+			return err
+		}
+	}
+	if err := check("capture", t.Capture); err != nil {
+		return err
+	}
+	return check("check", t.Check)
+}
+
+// fires the matching patterns from the destination square of every move, so they're aimed at whoever's up next
+func NewPatternTriggerListener(parentScene SceneInterface, triggers PatternTriggers, patterns map[string]pattern.File) MoveListener {
+	return func(event MoveEvent) error {
+		names := []string{triggers.Move[TypeToChessPiece(event.Piece.Type)]}
+		if !event.Captured.IsEmpty() {
+			names = append(names, triggers.Capture)
+		}
+		if event.Check {
+			names = append(names, triggers.Check)
+		}
+
+		x, y := GetBoardDrawingCoords(SquareToNative(event.Move.To), 0, 0)
+		for _, name := range names {
+			if name == "" {
+				continue
+			}
+			if err := SpawnPattern(parentScene, patterns[name], x, y); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
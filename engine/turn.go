@@ -0,0 +1,84 @@
+package engine
+
+import "fmt"
+
+// keeps a text sprite up to date with whose move it is
+const ComponentTypeTurnIndicator = "component-turn-indicator"
+
+type ComponentTurnIndicator struct {
+	Component
+	text TextSpriteInterface
+}
+
+type ComponentTurnIndicatorInterface interface {
+	ComponentInterface
+}
+
+func NewComponentTurnIndicator(parent ActorInterface, text TextSpriteInterface) (ComponentTurnIndicatorInterface, error) {
+	return &ComponentTurnIndicator{
+		Component: Component{parent, ComponentTypeTurnIndicator},
+		text:      text,
+	}, nil
+}
+
+func (c *ComponentTurnIndicator) Update(sim SimClockInterface) error {
+	scene, ok := c.parentActor.GetParentScene().(ChessSceneInterface)
+	if !ok {
+		return fmt.Errorf("turn indicator %s is not part of a chess scene", c.parentActor.GetId())
+	}
+	side, asked := scene.GetTakebackRequest()
+	switch {
+	case scene.GetOutcome().IsOver():
+		c.text.SetText(scene.GetOutcome().String())
+	case asked:
+		c.text.SetText(string(side) + " wants to take back their move: y to agree, n to say no")
+	case scene.GetSettings().Mode == GameModeRealtime:
+		if checked, inCheck := scene.GetCheckedSide(); inCheck {
+			c.text.SetText("real-time: " + string(checked) + " is in check and has to move")
+		} else {
+			c.text.SetText("real-time: any piece off cooldown can move")
+		}
+	case scene.GetPosition().InCheck(BoardSideToColor(scene.GetTurn())):
+		c.text.SetText(string(scene.GetTurn()) + " to move (check)")
+	default:
+		c.text.SetText(string(scene.GetTurn()) + " to move")
+	}
+	return nil
+}
+
+const ActorTypeTurnIndicator = "actor-turn-indicator"
+
+// sits just under the board
+func NewActorTurnIndicator(parentScene SceneInterface, id string) (ActorInterface, error) {
+	actor := Actor{
+		parentScene: parentScene,
+		actorType:   ActorTypeTurnIndicator,
+		id:          id,
+		components:  make([]ComponentInterface, 0),
+	}
+
+	text, err := NewTextSprite("")
+	if err != nil {
+		return nil, err
+	}
+	textComp, err := NewComponentDrawable(&actor, text, RenderLayerUI)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, textComp)
+
+	y := (ScreenHeight+BoardHeight)/2 + 8
+	worldly, err := NewComponentWorldly(&actor, 0, y, ScreenWidth, TextLineHeight, 0)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, worldly)
+
+	indicator, err := NewComponentTurnIndicator(&actor, text)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, indicator)
+
+	return &actor, nil
+}
@@ -0,0 +1,79 @@
+package engine
+
+import "image/color"
+
+var ButtonColor = color.RGBA{0x3a, 0x3a, 0x3a, 0xff}
+
+// labelled box that does something when pressed
+const ActorTypeButton = "actor-button"
+
+func NewActorButton(parentScene SceneInterface, id, label string, x, y, w, h float64, onPress ClickListener) (ActorInterface, error) {
+	actor := Actor{
+		parentScene: parentScene,
+		actorType:   ActorTypeButton,
+		id:          id,
+		components:  make([]ComponentInterface, 0),
+	}
+
+	backdrop, err := NewColorSprite(1, 1, ButtonColor)
+	if err != nil {
+		return nil, err
+	}
+	backdropComp, err := NewComponentDrawable(&actor, backdrop, RenderLayerUI)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, backdropComp)
+
+	text, err := NewTextSprite(label)
+	if err != nil {
+		return nil, err
+	}
+	textComp, err := NewComponentDrawable(&actor, text, RenderLayerUI)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, textComp)
+
+	worldly, err := NewComponentWorldly(&actor, x, y, w, h, 0)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, worldly)
+
+	clickableComp, err := NewComponentClickable(&actor)
+	if err != nil {
+		return nil, err
+	}
+	clickableComp.AddStateListener(MouseStatePressed, onPress)
+	actor.components = append(actor.components, clickableComp)
+
+	return &actor, nil
+}
+
+// invisible region that does something when pressed
+const ActorTypeClickArea = "actor-click-area"
+
+func NewActorClickArea(parentScene SceneInterface, id string, x, y, w, h float64, onPress ClickListener) (ActorInterface, error) {
+	actor := Actor{
+		parentScene: parentScene,
+		actorType:   ActorTypeClickArea,
+		id:          id,
+		components:  make([]ComponentInterface, 0),
+	}
+
+	worldly, err := NewComponentWorldly(&actor, x, y, w, h, 0)
+	if err != nil {
+		return nil, err
+	}
+	actor.components = append(actor.components, worldly)
+
+	clickableComp, err := NewComponentClickable(&actor)
+	if err != nil {
+		return nil, err
+	}
+	clickableComp.AddStateListener(MouseStatePressed, onPress)
+	actor.components = append(actor.components, clickableComp)
+
+	return &actor, nil
+}
@@ -1,14 +1,93 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"strings"
 
 	"github.com/hajimehoshi/ebiten"
+	"github.com/val-is/bullet-hell-chess/chess"
 	"github.com/val-is/bullet-hell-chess/engine"
+	"github.com/val-is/bullet-hell-chess/notation"
+	"github.com/val-is/bullet-hell-chess/pattern"
 )
 
 func main() {
-	g, err := engine.NewGameInstance()
+	mode := flag.String("mode", string(engine.GameModeTurns), "turns or realtime")
+	input := flag.String("input", string(engine.InputModeDrag), "drag or click")
+	timeControl := flag.String("time", "1+0",
+		"time control as minutes+seconds (presets: "+strings.Join(chess.TimeControlPresets, ", ")+") or none")
+	delay := flag.String("delay", string(chess.DelayFischer), "how the increment is applied: fischer, bronstein or simple")
+	penalty := flag.String("penalty", string(engine.HitPenaltyClockTime), "what getting hit costs: none, clock, forfeit or piece")
+	lives := flag.Int("lives", engine.DefaultGameSettings().Lives, "hits before a player loses, 0 for unlimited")
+	triggers := flag.String("triggers", engine.DefaultTriggerFile, "file mapping moves to bullet patterns, empty for no bullets")
+	takebacks := flag.String("takebacks", string(engine.TakebackAsk), "none, free (undo and redo) or ask (the other player has to agree)")
+	fen := flag.String("fen", notation.StartingFEN, "position to start from")
+	white := flag.String("white", "?", "white player's name, for the saved game")
+	black := flag.String("black", "?", "black player's name, for the saved game")
+	pgnDir := flag.String("pgn", engine.DefaultPGNDir, "directory finished games are saved to as pgn, empty to not save them")
+	replay := flag.String("replay", "", "pgn file to step through instead of playing")
+	checkPatterns := flag.Bool("check-patterns", false, "check every bullet pattern file and exit")
+	flag.Parse()
+
+	if *checkPatterns {
+		problems := pattern.ValidateDir(engine.PatternDir)
+		for _, problem := range problems {
+			fmt.Println(problem)
+		}
+		if len(problems) > 0 {
+			os.Exit(1)
+		}
+		fmt.Println("All patterns OK")
+		return
+	}
+
+	settings := engine.DefaultGameSettings()
+	gameMode, err := engine.ParseGameMode(*mode)
+	if err != nil {
+		log.Fatalf("Bad command line: %s", err)
+	}
+	settings.Mode = gameMode
+	inputMode, err := engine.ParseInputMode(*input)
+	if err != nil {
+		log.Fatalf("Bad command line: %s", err)
+	}
+	settings.InputMode = inputMode
+	delayMode, err := chess.ParseDelayMode(*delay)
+	if err != nil {
+		log.Fatalf("Bad command line: %s", err)
+	}
+	settings.TimeControl, err = chess.ParseTimeControl(*timeControl, delayMode)
+	if err != nil {
+		log.Fatalf("Bad command line: %s", err)
+	}
+	settings.HitPenalty, err = engine.ParseHitPenalty(*penalty)
+	if err != nil {
+		log.Fatalf("Bad command line: %s", err)
+	}
+	settings.Takebacks, err = engine.ParseTakebackMode(*takebacks)
+	if err != nil {
+		log.Fatalf("Bad command line: %s", err)
+	}
+	settings.Lives = *lives
+	settings.TriggerFile = *triggers
+	if _, err := notation.ParseFEN(*fen); err != nil {
+		log.Fatalf("Bad command line: %s", err)
+	}
+	settings.FEN = *fen
+	settings.WhiteName = *white
+	settings.BlackName = *black
+	settings.PGNDir = *pgnDir
+	if *replay != "" {
+		if _, err := engine.LoadPGNFile(*replay); err != nil {
+			log.Fatalf("Bad pgn: %s", err)
+		}
+	}
+	settings.ReplayFile = *replay
+
+	g, err := engine.NewGameInstance(settings)
 	if err != nil {
 		log.Fatalf("Error when initializing game: %s", err)
 	}
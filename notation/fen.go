@@ -0,0 +1,185 @@
+package notation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+)
+
+const StartingFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
+// white's pieces are upper case, black's lower
+func fenLetter(piece chess.Piece) byte {
+	if piece.Type == chess.Pawn {
+		if piece.Color == chess.White {
+			return 'P'
+		}
+		return 'p'
+	}
+	letter := pieceLetters[piece.Type][0]
+	if piece.Color == chess.Black {
+		letter += 'a' - 'A'
+	}
+	return letter
+}
+
+func pieceFromFENLetter(letter byte) (chess.Piece, bool) {
+	color := chess.White
+	if letter >= 'a' && letter <= 'z' {
+		color, letter = chess.Black, letter-('a'-'A')
+	}
+	if letter == 'P' {
+		return chess.Piece{Color: color, Type: chess.Pawn}, true
+	}
+	if t := pieceTypeFromLetter(letter); t != chess.NoPieceType {
+		return chess.Piece{Color: color, Type: t}, true
+	}
+	return chess.NoPiece, false
+}
+
+// the castling field, in the order FEN writes it
+var fenCastling = []struct {
+	letter byte
+	right  chess.CastlingRights
+}{
+	{'K', chess.WhiteKingside},
+	{'Q', chess.WhiteQueenside},
+	{'k', chess.BlackKingside},
+	{'q', chess.BlackQueenside},
+}
+
+// reads a position from FEN. the move counters can be left off, in which case they start at 0 and 1
+// anything that couldn't come up in a real game (missing kings, pawns on the back rank, castling without
+// the pieces in place, the side that just moved still in check) is rejected
+func ParseFEN(fen string) (*chess.Position, error) {
+	fields := strings.Fields(fen)
+	if len(fields) != 4 && len(fields) != 6 {
+		return nil, fmt.Errorf("fen %q: expected 6 fields, got %d", fen, len(fields))
+	}
+	p := chess.NewEmptyPosition()
+
+	ranks := strings.Split(fields[0], "/")
+	if len(ranks) != 8 {
+		return nil, fmt.Errorf("fen %q: expected 8 ranks, got %d", fen, len(ranks))
+	}
+	for i, rankText := range ranks {
+		// fen starts from the 8th rank
+		rank := 7 - i
+		file := 0
+		for j := 0; j < len(rankText); j++ {
+			c := rankText[j]
+			if c >= '1' && c <= '8' {
+				file += int(c - '0')
+				continue
+			}
+			piece, ok := pieceFromFENLetter(c)
+			if !ok {
+				return nil, fmt.Errorf("fen %q: unknown piece %q on rank %d", fen, c, rank+1)
+			}
+			if file > 7 {
+				return nil, fmt.Errorf("fen %q: rank %d covers more than 8 squares", fen, rank+1)
+			}
+			p.Board[chess.NewSquare(file, rank)] = piece
+			file++
+		}
+		if file != 8 {
+			return nil, fmt.Errorf("fen %q: rank %d covers %d squares, not 8", fen, rank+1, file)
+		}
+	}
+
+	switch fields[1] {
+	case "w":
+		p.SideToMove = chess.White
+	case "b":
+		p.SideToMove = chess.Black
+	default:
+		return nil, fmt.Errorf("fen %q: side to move should be w or b, not %q", fen, fields[1])
+	}
+
+	if fields[2] != "-" {
+		for i := 0; i < len(fields[2]); i++ {
+			found := false
+			for _, castling := range fenCastling {
+				if fields[2][i] == castling.letter && p.Castling&castling.right == 0 {
+					p.Castling |= castling.right
+					found = true
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("fen %q: bad castling rights %q", fen, fields[2])
+			}
+		}
+	}
+
+	if fields[3] != "-" {
+		square, err := ParseSquare(fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("fen %q: en passant: %s", fen, err)
+		}
+		p.EnPassant = square
+	}
+
+	if len(fields) == 6 {
+		halfmoves, err := strconv.Atoi(fields[4])
+		if err != nil || halfmoves < 0 {
+			return nil, fmt.Errorf("fen %q: bad halfmove clock %q", fen, fields[4])
+		}
+		fullmoves, err := strconv.Atoi(fields[5])
+		if err != nil || fullmoves < 1 {
+			return nil, fmt.Errorf("fen %q: bad fullmove number %q", fen, fields[5])
+		}
+		p.HalfmoveClock = halfmoves
+		p.FullmoveNumber = fullmoves
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("fen %q: %s", fen, err)
+	}
+	return p, nil
+}
+
+// the other way round from ParseFEN
+func FormatFEN(p *chess.Position) string {
+	var b strings.Builder
+	for rank := 7; rank >= 0; rank-- {
+		empty := 0
+		for file := 0; file < 8; file++ {
+			piece := p.Board[chess.NewSquare(file, rank)]
+			if piece.IsEmpty() {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				b.WriteByte(byte('0' + empty))
+				empty = 0
+			}
+			b.WriteByte(fenLetter(piece))
+		}
+		if empty > 0 {
+			b.WriteByte(byte('0' + empty))
+		}
+		if rank > 0 {
+			b.WriteByte('/')
+		}
+	}
+
+	if p.SideToMove == chess.White {
+		b.WriteString(" w ")
+	} else {
+		b.WriteString(" b ")
+	}
+
+	if p.Castling == chess.NoCastling {
+		b.WriteByte('-')
+	}
+	for _, castling := range fenCastling {
+		if p.Castling&castling.right != 0 {
+			b.WriteByte(castling.letter)
+		}
+	}
+
+	fmt.Fprintf(&b, " %s %d %d", FormatSquare(p.EnPassant), p.HalfmoveClock, p.FullmoveNumber)
+	return b.String()
+}
@@ -0,0 +1,85 @@
+package notation
+
+import (
+	"testing"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+)
+
+func TestFENRoundTrip(t *testing.T) {
+	for _, fen := range []string{
+		StartingFEN,
+		"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1",
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+		"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+		"r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1",
+		"rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8",
+		"4k3/8/8/8/8/8/8/R3K3 w Q - 37 112",
+		enPassantFEN,
+	} {
+		p, err := ParseFEN(fen)
+		if err != nil {
+			t.Errorf("%s: %s", fen, err)
+			continue
+		}
+		if formatted := FormatFEN(p); formatted != fen {
+			t.Errorf("%s came back as %s", fen, formatted)
+		}
+	}
+
+	if fen := FormatFEN(chess.NewStartingPosition()); fen != StartingFEN {
+		t.Errorf("starting position formatted as %s", fen)
+	}
+}
+
+func TestParseFEN(t *testing.T) {
+	p := mustParseFEN(t, "r3k2r/8/8/8/8/8/8/R3K2R b Kq - 12 40")
+	if p.SideToMove != chess.Black {
+		t.Errorf("side to move %s, want black", p.SideToMove)
+	}
+	if p.Castling != chess.WhiteKingside|chess.BlackQueenside {
+		t.Errorf("castling %v, want Kq", p.Castling)
+	}
+	if p.HalfmoveClock != 12 || p.FullmoveNumber != 40 {
+		t.Errorf("move counters %d %d, want 12 40", p.HalfmoveClock, p.FullmoveNumber)
+	}
+	if piece := p.PieceAt(mustParseSquare(t, "h8")); piece != (chess.Piece{Color: chess.Black, Type: chess.Rook}) {
+		t.Errorf("h8 has %v, want a black rook", piece)
+	}
+
+	// the counters can be left off
+	p = mustParseFEN(t, "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq -")
+	if p.HalfmoveClock != 0 || p.FullmoveNumber != 1 {
+		t.Errorf("move counters %d %d without them in the fen, want 0 1", p.HalfmoveClock, p.FullmoveNumber)
+	}
+}
+
+func TestParseFENErrors(t *testing.T) {
+	for _, fen := range []string{
+		"",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP w KQkq - 0 1",
+		"rnbqkbnr/pppppppp/9/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		"rnbqkbnr/pppppppp/7/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		"rnbqkbnrr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		"rnbqkbnr/ppppxppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR x KQkq - 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkqK - 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w X - 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq e9 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - -1 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 0",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - x 1",
+		// positions that can't come up in a game
+		"rnbq1bnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQ - 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKKNR w kq - 0 1",
+		"rnbqkbnP/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQq - 0 1",
+		"4k3/8/8/8/8/8/8/4K3 w K - 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq e6 0 1",
+		"4k3/8/8/8/8/8/8/R3K2r b - - 0 1",
+	} {
+		if p, err := ParseFEN(fen); err == nil {
+			t.Errorf("%q parsed to %s, want an error", fen, FormatFEN(p))
+		}
+	}
+}
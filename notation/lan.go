@@ -0,0 +1,112 @@
+package notation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+)
+
+// long algebraic notation, SAN with the whole from square and a - or x between the squares:
+// Ng1-f3, e4xd5, e7-e8=Q+. castling is O-O or O-O-O like in SAN
+func FormatLAN(p *chess.Position, m chess.Move) string {
+	if IsNullMove(m) {
+		return NullMoveSAN
+	}
+	if castling, ok := castlingText(p, m); ok {
+		return castling + checkSuffix(p, m)
+	}
+
+	var b strings.Builder
+	b.WriteString(pieceLetters[p.PieceAt(m.From).Type])
+	b.WriteString(FormatSquare(m.From))
+	if p.CaptureSquare(m) != chess.NoSquare {
+		b.WriteByte('x')
+	} else {
+		b.WriteByte('-')
+	}
+	b.WriteString(FormatSquare(m.To))
+	if m.Promotion != chess.NoPieceType {
+		b.WriteByte('=')
+		b.WriteString(pieceLetters[m.Promotion])
+	}
+	b.WriteString(checkSuffix(p, m))
+	return b.String()
+}
+
+// reads a move in LAN for the side to move. check marks, annotations and e.p. are allowed but not needed,
+// the - between squares and the = before a promotion can be left out, and the piece letter has to match
+// what's on the from square. NullMoveSAN comes back as NullMove
+func ParseLAN(p *chess.Position, lan string) (chess.Move, error) {
+	text, enPassant := trimMoveSuffixes(lan)
+	if text == NullMoveSAN {
+		return NullMove, nil
+	}
+	if move, ok, err := parseCastling(p, text); ok {
+		if err != nil {
+			return chess.Move{}, fmt.Errorf("%s: %s", lan, err)
+		}
+		return move, nil
+	}
+
+	pieceType := chess.Pawn
+	if len(text) > 0 {
+		if t := pieceTypeFromLetter(text[0]); t != chess.NoPieceType {
+			pieceType = t
+			text = text[1:]
+		}
+	}
+	if len(text) < 4 {
+		return chess.Move{}, fmt.Errorf("%s: not a move", lan)
+	}
+	from, err := ParseSquare(text[:2])
+	if err != nil {
+		return chess.Move{}, fmt.Errorf("%s: not a move", lan)
+	}
+	text = text[2:]
+	capture := false
+	switch text[0] {
+	case 'x':
+		capture = true
+		text = text[1:]
+	case '-':
+		text = text[1:]
+	}
+	if len(text) < 2 {
+		return chess.Move{}, fmt.Errorf("%s: not a move", lan)
+	}
+	to, err := ParseSquare(text[:2])
+	if err != nil {
+		return chess.Move{}, fmt.Errorf("%s: not a move", lan)
+	}
+
+	move := chess.Move{From: from, To: to}
+	switch promotion := strings.TrimPrefix(text[2:], "="); {
+	case promotion == "":
+	case pieceType != chess.Pawn:
+		return chess.Move{}, fmt.Errorf("%s: only pawns promote", lan)
+	default:
+		move.Promotion = promotionFromLetter(promotion)
+		if move.Promotion == chess.NoPieceType {
+			return chess.Move{}, fmt.Errorf("%s: bad promotion", lan)
+		}
+	}
+
+	piece := p.PieceAt(from)
+	if piece.IsEmpty() || piece.Color != p.SideToMove {
+		return chess.Move{}, fmt.Errorf("%s: no %s piece on %s", lan, p.SideToMove, FormatSquare(from))
+	}
+	if piece.Type != pieceType {
+		return chess.Move{}, fmt.Errorf("%s: the piece on %s is a %s, not a %s", lan, FormatSquare(from), piece.Type, pieceType)
+	}
+	if !p.IsLegal(move) {
+		if move.Promotion == chess.NoPieceType && p.IsPromotion(from, to) {
+			return chess.Move{}, fmt.Errorf("%s: promotion needs a piece", lan)
+		}
+		return chess.Move{}, fmt.Errorf("%s: not a legal move for %s", lan, p.SideToMove)
+	}
+	if err := checkCapture(p, move, capture, enPassant); err != nil {
+		return chess.Move{}, fmt.Errorf("%s: %s", lan, err)
+	}
+	return move, nil
+}
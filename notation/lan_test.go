@@ -0,0 +1,117 @@
+package notation
+
+import (
+	"testing"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+)
+
+func TestFormatLAN(t *testing.T) {
+	for _, tc := range []struct {
+		fen string
+		uci string
+		lan string
+	}{
+		{StartingFEN, "e2e4", "e2-e4"},
+		{StartingFEN, "g1f3", "Ng1-f3"},
+		{twoKnightsFEN, "b6d7", "Nb6xd7+"},
+		{enPassantFEN, "e5f6", "e5xf6"},
+		{threeQueensFEN, "h5e5", "Qh5-e5+"},
+		{promotionFEN, "a7a8q", "a7-a8=Q+"},
+		{promotionFEN, "a7a8n", "a7-a8=N"},
+		{backRankFEN, "a1a8", "Ra1-a8#"},
+		{castlingFEN, "e1g1", "O-O"},
+		{castlingFEN, "e1c1", "O-O-O"},
+	} {
+		p := mustParseFEN(t, tc.fen)
+		move, err := ParseUCIMove(p, tc.uci)
+		if err != nil {
+			t.Errorf("%s: %s", tc.fen, err)
+			continue
+		}
+		if lan := FormatLAN(p, move); lan != tc.lan {
+			t.Errorf("%s %s formatted as %s, want %s", tc.fen, tc.uci, lan, tc.lan)
+		}
+	}
+
+	if lan := FormatLAN(chess.NewStartingPosition(), NullMove); lan != NullMoveSAN {
+		t.Errorf("null move formatted as %s", lan)
+	}
+}
+
+func TestParseLAN(t *testing.T) {
+	for _, tc := range []struct {
+		fen string
+		lan string
+		uci string
+	}{
+		{StartingFEN, "e2-e4", "e2e4"},
+		{StartingFEN, "e2e4", "e2e4"},
+		{StartingFEN, "Ng1-f3!", "g1f3"},
+		{StartingFEN, "--", NullMoveUCI},
+		{twoKnightsFEN, "Nb6xd7+", "b6d7"},
+		{twoKnightsFEN, "Ne5-d7", "e5d7"},
+		{enPassantFEN, "e5xf6 e.p.", "e5f6"},
+		{promotionFEN, "a7-a8=Q+", "a7a8q"},
+		{promotionFEN, "a7a8R", "a7a8r"},
+		{castlingFEN, "O-O-O", "e1c1"},
+	} {
+		p := mustParseFEN(t, tc.fen)
+		move, err := ParseLAN(p, tc.lan)
+		if err != nil {
+			t.Errorf("%s: %s", tc.fen, err)
+			continue
+		}
+		if uci := FormatUCI(move); uci != tc.uci {
+			t.Errorf("%s %s parsed to %s, want %s", tc.fen, tc.lan, uci, tc.uci)
+		}
+	}
+}
+
+func TestParseLANErrors(t *testing.T) {
+	for _, tc := range []struct {
+		fen string
+		lan string
+	}{
+		// wrong piece letter, or no piece of the side to move
+		{StartingFEN, "Ne2-e4"},
+		{StartingFEN, "g1-f3"},
+		{StartingFEN, "e7-e5"},
+		{StartingFEN, "e3-e4"},
+		// illegal
+		{StartingFEN, "e2-e5"},
+		{StartingFEN, "e2xe4"},
+		{enPassantFEN, "d2-d4 e.p."},
+		{enPassantFEN, "e5xd6"},
+		{promotionFEN, "a7-a8"},
+		{promotionFEN, "a7-a8=K"},
+		{twoKnightsFEN, "Nb6-d7=Q"},
+		// not a move at all
+		{StartingFEN, ""},
+		{StartingFEN, "e2"},
+		{StartingFEN, "e2-"},
+		{StartingFEN, "e2-z4"},
+	} {
+		p := mustParseFEN(t, tc.fen)
+		if move, err := ParseLAN(p, tc.lan); err == nil {
+			t.Errorf("%s %q parsed to %s, want an error", tc.fen, tc.lan, FormatUCI(move))
+		}
+	}
+}
+
+func TestLANRoundTrip(t *testing.T) {
+	for _, fen := range []string{StartingFEN, enPassantFEN, fourKnightsFEN, promotionFEN, castlingFEN} {
+		p := mustParseFEN(t, fen)
+		for _, move := range p.LegalMoves() {
+			lan := FormatLAN(p, move)
+			parsed, err := ParseLAN(p, lan)
+			if err != nil {
+				t.Errorf("%s: %s came back with %s", fen, lan, err)
+				continue
+			}
+			if parsed != move {
+				t.Errorf("%s: %s parsed to %s, want %s", fen, lan, FormatUCI(parsed), FormatUCI(move))
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package notation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+)
+
+// one [Name "Value"] pair from the top of a pgn
+type PGNTag struct {
+	Name  string
+	Value string
+}
+
+// the tags every pgn needs, in the order they have to come first
+var PGNSevenTagRoster = []string{"Event", "Site", "Date", "Round", "White", "Black", "Result"}
+
+// comment command for a piece taken off the board, like [%clk] is for clock times
+const pgnRemoveCommand = "[%remove"
+
+// pgn lines shouldn't go past this
+const pgnLineWidth = 79
+
+// writes the game out with the given tags, the seven tag roster first (filled with "?" for any that are missing)
+// and anything else after. games that didn't start from the usual position get SetUp and FEN tags
+// comment is put at the very end of the moves, before the result, if it isn't empty
+// the moves are worked out in SAN by playing the record through again from the start
+func FormatPGN(g *chess.Game, tags []PGNTag, result chess.Result, comment string) string {
+	var b strings.Builder
+
+	values := make(map[string]string)
+	for _, tag := range tags {
+		values[tag.Name] = tag.Value
+	}
+	values["Result"] = result.String()
+	for _, name := range PGNSevenTagRoster {
+		value, ok := values[name]
+		if !ok {
+			value = "?"
+		}
+		writePGNTag(&b, name, value)
+	}
+	for _, tag := range tags {
+		if isSevenTagRoster(tag.Name) || tag.Name == "SetUp" || tag.Name == "FEN" {
+			continue
+		}
+		writePGNTag(&b, tag.Name, tag.Value)
+	}
+	if start := FormatFEN(g.Start); start != StartingFEN {
+		writePGNTag(&b, "SetUp", "1")
+		writePGNTag(&b, "FEN", start)
+	}
+	b.WriteByte('\n')
+
+	tokens := make([]string, 0, len(g.Record)+2)
+	position := g.Start.Copy()
+	// black's move only needs its number when something came between it and white's
+	needNumber := true
+	for _, entry := range g.Record {
+		var san string
+		switch entry.Kind {
+		case chess.RecordComment:
+			tokens = appendPGNComment(tokens, entry.Comment)
+			needNumber = true
+			continue
+		case chess.RecordRemoval:
+			// the command lets the pgn be read back in, anything else just shows the comment
+			square := FormatSquare(entry.Square)
+			tokens = appendPGNComment(tokens, fmt.Sprintf("%s %s] %s %s on %s taken off",
+				pgnRemoveCommand, square, entry.Piece.Color, entry.Piece.Type, square))
+			position.SetPiece(entry.Square, chess.NoPiece)
+			needNumber = true
+			continue
+		case chess.RecordPass:
+			san = NullMoveSAN
+		case chess.RecordMove:
+			san = FormatSAN(position, entry.Move)
+		}
+
+		if position.SideToMove == chess.White {
+			tokens = append(tokens, fmt.Sprintf("%d.", position.FullmoveNumber))
+		} else if needNumber {
+			tokens = append(tokens, fmt.Sprintf("%d...", position.FullmoveNumber))
+		}
+		tokens = append(tokens, san)
+		needNumber = false
+
+		if entry.Kind == chess.RecordPass {
+			position.Pass()
+		} else if err := position.MakeMove(entry.Move); err != nil {
+			// can't happen for a record the game kept itself
+			break
+		}
+	}
+	if comment != "" {
+		tokens = appendPGNComment(tokens, comment)
+	}
+	tokens = append(tokens, result.String())
+
+	lineLength := 0
+	for i, token := range tokens {
+		if i > 0 {
+			if lineLength+1+len(token) > pgnLineWidth {
+				b.WriteByte('\n')
+				lineLength = 0
+			} else {
+				b.WriteByte(' ')
+				lineLength++
+			}
+		}
+		b.WriteString(token)
+		lineLength += len(token)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func isSevenTagRoster(name string) bool {
+	for _, roster := range PGNSevenTagRoster {
+		if name == roster {
+			return true
+		}
+	}
+	return false
+}
+
+func writePGNTag(b *strings.Builder, name, value string) {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	fmt.Fprintf(b, "[%s \"%s\"]\n", name, value)
+}
+
+// a word at a time so long comments can wrap, comments can't have a closing brace in them
+func appendPGNComment(tokens []string, comment string) []string {
+	words := strings.Fields(strings.ReplaceAll(comment, "}", ")"))
+	if len(words) == 0 {
+		return append(tokens, "{}")
+	}
+	words[0] = "{" + words[0]
+	words[len(words)-1] += "}"
+	return append(tokens, words...)
+}
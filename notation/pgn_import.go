@@ -0,0 +1,444 @@
+package notation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+)
+
+// one game read from a pgn, moves and variations as a tree hanging off Root
+type PGNGame struct {
+	Tags   []PGNTag
+	Root   *PGNNode
+	Result chess.Result
+}
+
+func (g *PGNGame) Tag(name string) (string, bool) {
+	for _, tag := range g.Tags {
+		if tag.Name == name {
+			return tag.Value, true
+		}
+	}
+	return "", false
+}
+
+// the position after a move, Root is the starting position with no move
+type PGNNode struct {
+	// NullMove for a null move
+	Move chess.Move
+	// as written in the file
+	SAN      string
+	Position *chess.Position
+	// comment just before the move, only at the start of a variation
+	CommentBefore string
+	Comment       string
+	NAGs          []int
+	Parent        *PGNNode
+	// the first child carries on the line this node is on, the rest are variations on it
+	Children []*PGNNode
+}
+
+func (n *PGNNode) IsNull() bool {
+	return n.Parent != nil && IsNullMove(n.Move)
+}
+
+// the number written before the move, and whether it was white's
+func (n *PGNNode) MoveNumber() (int, bool) {
+	if n.Parent == nil {
+		return n.Position.FullmoveNumber, n.Position.SideToMove == chess.White
+	}
+	before := n.Parent.Position
+	return before.FullmoveNumber, before.SideToMove == chess.White
+}
+
+// where in the file something went wrong, both 1 indexed
+type PGNError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *PGNError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+type pgnTokenKind int
+
+const (
+	pgnTokenEOF            pgnTokenKind = iota
+	pgnTokenSymbol         pgnTokenKind = iota
+	pgnTokenString         pgnTokenKind = iota
+	pgnTokenComment        pgnTokenKind = iota
+	pgnTokenNAG            pgnTokenKind = iota
+	pgnTokenPeriod         pgnTokenKind = iota
+	pgnTokenOpenTag        pgnTokenKind = iota
+	pgnTokenCloseTag       pgnTokenKind = iota
+	pgnTokenOpenVariation  pgnTokenKind = iota
+	pgnTokenCloseVariation pgnTokenKind = iota
+)
+
+type pgnToken struct {
+	kind   pgnTokenKind
+	text   string
+	line   int
+	column int
+}
+
+type pgnLexer struct {
+	text   string
+	pos    int
+	line   int
+	column int
+}
+
+func (l *pgnLexer) errorf(line, column int, format string, args ...interface{}) error {
+	return &PGNError{line, column, fmt.Sprintf(format, args...)}
+}
+
+func (l *pgnLexer) peekByte() byte {
+	if l.pos >= len(l.text) {
+		return 0
+	}
+	return l.text[l.pos]
+}
+
+func (l *pgnLexer) nextByte() byte {
+	c := l.text[l.pos]
+	l.pos++
+	if c == '\n' {
+		l.line++
+		l.column = 1
+	} else {
+		l.column++
+	}
+	return c
+}
+
+func isPGNSymbolByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte("_+#=:-/!?", c) >= 0
+}
+
+func (l *pgnLexer) next() (pgnToken, error) {
+	for l.pos < len(l.text) {
+		c := l.peekByte()
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			l.nextByte()
+		case c == '%' && l.column == 1:
+			// escaped line, meant for other programs
+			for l.pos < len(l.text) && l.peekByte() != '\n' {
+				l.nextByte()
+			}
+		default:
+			return l.token()
+		}
+	}
+	return pgnToken{pgnTokenEOF, "", l.line, l.column}, nil
+}
+
+func (l *pgnLexer) token() (pgnToken, error) {
+	line, column := l.line, l.column
+	token := func(kind pgnTokenKind, text string) (pgnToken, error) {
+		return pgnToken{kind, text, line, column}, nil
+	}
+	c := l.nextByte()
+	switch c {
+	case '[':
+		return token(pgnTokenOpenTag, "[")
+	case ']':
+		return token(pgnTokenCloseTag, "]")
+	case '(':
+		return token(pgnTokenOpenVariation, "(")
+	case ')':
+		return token(pgnTokenCloseVariation, ")")
+	case '.':
+		return token(pgnTokenPeriod, ".")
+	case '*':
+		return token(pgnTokenSymbol, "*")
+	case '"':
+		var b strings.Builder
+		for {
+			if l.pos >= len(l.text) || l.peekByte() == '\n' {
+				return pgnToken{}, l.errorf(line, column, "string isn't closed")
+			}
+			c := l.nextByte()
+			if c == '"' {
+				return token(pgnTokenString, b.String())
+			}
+			if c == '\\' && (l.peekByte() == '"' || l.peekByte() == '\\') {
+				c = l.nextByte()
+			}
+			b.WriteByte(c)
+		}
+	case '{':
+		start := l.pos
+		for l.pos < len(l.text) && l.peekByte() != '}' {
+			l.nextByte()
+		}
+		if l.pos >= len(l.text) {
+			return pgnToken{}, l.errorf(line, column, "comment isn't closed")
+		}
+		comment := l.text[start:l.pos]
+		l.nextByte()
+		return token(pgnTokenComment, strings.Join(strings.Fields(comment), " "))
+	case ';':
+		start := l.pos
+		for l.pos < len(l.text) && l.peekByte() != '\n' {
+			l.nextByte()
+		}
+		return token(pgnTokenComment, strings.TrimSpace(l.text[start:l.pos]))
+	case '$':
+		start := l.pos
+		for l.peekByte() >= '0' && l.peekByte() <= '9' {
+			l.nextByte()
+		}
+		if start == l.pos {
+			return pgnToken{}, l.errorf(line, column, "$ should be followed by a number")
+		}
+		return token(pgnTokenNAG, l.text[start:l.pos])
+	}
+	if isPGNSymbolByte(c) {
+		start := l.pos - 1
+		for isPGNSymbolByte(l.peekByte()) {
+			l.nextByte()
+		}
+		return token(pgnTokenSymbol, l.text[start:l.pos])
+	}
+	return pgnToken{}, l.errorf(line, column, "unexpected %q", c)
+}
+
+type pgnParser struct {
+	lexer pgnLexer
+	token pgnToken
+}
+
+func (p *pgnParser) advance() error {
+	token, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.token = token
+	return nil
+}
+
+func (p *pgnParser) errorf(token pgnToken, format string, args ...interface{}) error {
+	return &PGNError{token.line, token.column, fmt.Sprintf(format, args...)}
+}
+
+// every game in the file, errors are *PGNError so they can say where the problem is
+func ParsePGN(text string) ([]*PGNGame, error) {
+	p := pgnParser{lexer: pgnLexer{text: text, line: 1, column: 1}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	games := make([]*PGNGame, 0)
+	for p.token.kind != pgnTokenEOF {
+		game, err := p.parseGame()
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, game)
+	}
+	return games, nil
+}
+
+func (p *pgnParser) parseGame() (*PGNGame, error) {
+	game := &PGNGame{Tags: make([]PGNTag, 0), Result: chess.ResultOngoing}
+	start := chess.NewStartingPosition()
+	for p.token.kind == pgnTokenOpenTag {
+		tagToken := p.token
+		tag, err := p.parseTag()
+		if err != nil {
+			return nil, err
+		}
+		game.Tags = append(game.Tags, tag)
+		if tag.Name == "FEN" {
+			if start, err = ParseFEN(tag.Value); err != nil {
+				return nil, p.errorf(tagToken, "%s", err)
+			}
+		}
+	}
+	game.Root = &PGNNode{Position: start, Children: make([]*PGNNode, 0)}
+	if err := p.parseMoves(game); err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+func (p *pgnParser) parseTag() (PGNTag, error) {
+	if err := p.advance(); err != nil {
+		return PGNTag{}, err
+	}
+	if p.token.kind != pgnTokenSymbol {
+		return PGNTag{}, p.errorf(p.token, "expected a tag name")
+	}
+	name := p.token.text
+	if err := p.advance(); err != nil {
+		return PGNTag{}, err
+	}
+	if p.token.kind != pgnTokenString {
+		return PGNTag{}, p.errorf(p.token, "expected the value of tag %s in quotes", name)
+	}
+	value := p.token.text
+	if err := p.advance(); err != nil {
+		return PGNTag{}, err
+	}
+	if p.token.kind != pgnTokenCloseTag {
+		return PGNTag{}, p.errorf(p.token, "expected ] to close tag %s", name)
+	}
+	return PGNTag{name, value}, p.advance()
+}
+
+func parsePGNResult(text string) (chess.Result, bool) {
+	for _, result := range []chess.Result{chess.ResultWhiteWins, chess.ResultBlackWins, chess.ResultDraw, chess.ResultOngoing} {
+		if text == result.String() {
+			return result, true
+		}
+	}
+	return chess.ResultOngoing, false
+}
+
+func isMoveNumber(text string) bool {
+	_, err := strconv.Atoi(text)
+	return err == nil
+}
+
+// runs until the result, the next game's tags or the end of the file
+func (p *pgnParser) parseMoves(game *PGNGame) error {
+	current := game.Root
+	// where to go back to when each open variation closes, with the ( for errors
+	type openVariation struct {
+		resume *PGNNode
+		token  pgnToken
+	}
+	stack := make([]openVariation, 0)
+	// a comment that came before any move in a variation belongs to that variation's first move
+	commentBefore := ""
+	startOfVariation := false
+
+	for {
+		token := p.token
+		switch token.kind {
+		case pgnTokenEOF, pgnTokenOpenTag:
+			if len(stack) > 0 {
+				return p.errorf(stack[len(stack)-1].token, "variation isn't closed")
+			}
+			return nil
+		case pgnTokenPeriod:
+		case pgnTokenString, pgnTokenCloseTag:
+			return p.errorf(token, "unexpected %q in the moves", token.text)
+		case pgnTokenComment:
+			if startOfVariation {
+				commentBefore = joinComments(commentBefore, token.text)
+				break
+			}
+			comment, err := applyRemovals(current.Position, token.text)
+			if err != nil {
+				return p.errorf(token, "%s", err)
+			}
+			current.Comment = joinComments(current.Comment, comment)
+		case pgnTokenNAG:
+			nag, err := strconv.Atoi(token.text)
+			if err != nil {
+				return p.errorf(token, "bad NAG $%s", token.text)
+			}
+			if current == game.Root {
+				return p.errorf(token, "NAG $%s comes before any move", token.text)
+			}
+			current.NAGs = append(current.NAGs, nag)
+		case pgnTokenOpenVariation:
+			if current.Parent == nil {
+				return p.errorf(token, "variation has no move to be an alternative to")
+			}
+			stack = append(stack, openVariation{current, token})
+			current = current.Parent
+			startOfVariation = true
+		case pgnTokenCloseVariation:
+			if len(stack) == 0 {
+				return p.errorf(token, "unexpected ), no variation is open")
+			}
+			if startOfVariation {
+				return p.errorf(token, "variation has no moves")
+			}
+			current = stack[len(stack)-1].resume
+			stack = stack[:len(stack)-1]
+		case pgnTokenSymbol:
+			if result, ok := parsePGNResult(token.text); ok {
+				if len(stack) > 0 {
+					return p.errorf(token, "game ends with %s inside a variation", token.text)
+				}
+				game.Result = result
+				return p.advance()
+			}
+			if isMoveNumber(token.text) {
+				break
+			}
+			node, err := p.playMove(current, token)
+			if err != nil {
+				return err
+			}
+			node.CommentBefore = commentBefore
+			commentBefore = ""
+			startOfVariation = false
+			current = node
+		}
+		if err := p.advance(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *pgnParser) playMove(current *PGNNode, token pgnToken) (*PGNNode, error) {
+	position := current.Position.Copy()
+	node := &PGNNode{SAN: token.text, Parent: current, Children: make([]*PGNNode, 0)}
+	move, err := ParseSAN(current.Position, token.text)
+	if err != nil {
+		return nil, p.errorf(token, "%s", err)
+	}
+	if IsNullMove(move) {
+		position.Pass()
+	} else if err := position.MakeMove(move); err != nil {
+		return nil, p.errorf(token, "%s", err)
+	}
+	node.Move = move
+	node.Position = position
+	current.Children = append(current.Children, node)
+	return node, nil
+}
+
+// pieces taken off outside of normal play are written as [%remove <square>] in the comment after the move,
+// see chess.Game.RemovePiece. they're taken off the position and left out of the comment
+func applyRemovals(position *chess.Position, comment string) (string, error) {
+	for {
+		start := strings.Index(comment, pgnRemoveCommand)
+		if start < 0 {
+			return comment, nil
+		}
+		end := strings.IndexByte(comment[start:], ']')
+		if end < 0 {
+			return "", fmt.Errorf("%s isn't closed", pgnRemoveCommand)
+		}
+		end += start
+		square, err := ParseSquare(strings.TrimSpace(comment[start+len(pgnRemoveCommand) : end]))
+		if err != nil {
+			return "", fmt.Errorf("%s: %s", pgnRemoveCommand, err)
+		}
+		if position.PieceAt(square).IsEmpty() {
+			return "", fmt.Errorf("%s: nothing on %s", pgnRemoveCommand, FormatSquare(square))
+		}
+		position.SetPiece(square, chess.NoPiece)
+		comment = strings.TrimSpace(comment[:start] + comment[end+1:])
+	}
+}
+
+func joinComments(a, b string) string {
+	if a == "" {
+		return b
+	}
+	if b == "" {
+		return a
+	}
+	return a + " " + b
+}
@@ -0,0 +1,294 @@
+package notation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+)
+
+// plays the moves in uci, "--" passes, "x<square>" takes a piece off and "{...}" is a comment
+func playGame(t *testing.T, start *chess.Position, steps ...string) *chess.Game {
+	t.Helper()
+	g := chess.NewGame(start)
+	for _, step := range steps {
+		switch {
+		case step == NullMoveSAN:
+			g.Pass()
+		case strings.HasPrefix(step, "x"):
+			g.RemovePiece(mustParseSquare(t, step[1:]))
+		case strings.HasPrefix(step, "{"):
+			g.Comment(strings.Trim(step, "{}"))
+		default:
+			move, err := ParseUCIMove(g.Position, step)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := g.Play(move); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return g
+}
+
+// the line the game went down, following the first child from the root
+func mainLine(game *PGNGame) []*PGNNode {
+	line := make([]*PGNNode, 0)
+	for node := game.Root; len(node.Children) > 0; {
+		node = node.Children[0]
+		line = append(line, node)
+	}
+	return line
+}
+
+func TestFormatPGN(t *testing.T) {
+	// white passes and then moves again, so black's turn in between is written as a pass too
+	g := playGame(t, chess.NewStartingPosition(), "e2e4", "e7e5", "g1f3", "{a comment}", "b8c6", "--", "f3e5")
+	tags := []PGNTag{{"White", "Alice"}, {"Black", "Bob"}, {"Annotator", `Someone "quoted"`}}
+	pgn := FormatPGN(g, tags, chess.ResultWhiteWins, "black resigns")
+
+	want := `[Event "?"]
+[Site "?"]
+[Date "?"]
+[Round "?"]
+[White "Alice"]
+[Black "Bob"]
+[Result "1-0"]
+[Annotator "Someone \"quoted\""]
+
+1. e4 e5 2. Nf3 {a comment} 2... Nc6 3. -- -- 4. Nxe5 {black resigns} 1-0
+`
+	if pgn != want {
+		t.Errorf("got\n%s\nwant\n%s", pgn, want)
+	}
+}
+
+func TestFormatPGNLineWidth(t *testing.T) {
+	steps := make([]string, 0)
+	for i := 0; i < 20; i++ {
+		steps = append(steps, "g1f3", "g8f6", "f3g1", "f6g8")
+	}
+	g := playGame(t, chess.NewStartingPosition(), steps...)
+	pgn := FormatPGN(g, nil, chess.ResultDraw, strings.Repeat("a fairly long comment ", 10))
+	for _, line := range strings.Split(pgn, "\n") {
+		if len(line) > pgnLineWidth {
+			t.Errorf("line is %d long: %s", len(line), line)
+		}
+	}
+}
+
+// everything the record can hold comes back out of the pgn: moves, passes, pieces taken off, comments,
+// moves out of turn and a start that isn't the usual one
+func TestPGNRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		fen   string
+		steps []string
+	}{
+		{StartingFEN, []string{"e2e4", "e7e5", "g1f3", "b8c6", "f1b5", "a7a6", "b5c6", "d7c6", "e1g1"}},
+		{StartingFEN, []string{"e2e4", "--", "d2d4", "{twice}", "d7d5", "xb1", "e4d5", "xd8", "g8f6"}},
+		// out of turn, white moves twice in a row
+		{StartingFEN, []string{"e2e4", "d2d4", "e7e5", "d4e5", "f7f5", "e5f6", "g8f6"}},
+		{enPassantFEN, []string{"e5f6", "g8f6", "--", "--", "xf6", "d2d4"}},
+		{"7k/P7/8/8/8/8/8/K7 w - - 0 60", []string{"a7a8n", "h8g7", "{only a knight}", "a8b6"}},
+	} {
+		g := playGame(t, mustParseFEN(t, tc.fen), tc.steps...)
+		tags := []PGNTag{{"Event", "round trip"}, {"Variant", "bullet hell"}}
+		pgn := FormatPGN(g, tags, chess.ResultOngoing, "")
+
+		games, err := ParsePGN(pgn)
+		if err != nil {
+			t.Errorf("%s\n%s", pgn, err)
+			continue
+		}
+		if len(games) != 1 {
+			t.Errorf("%s\nread %d games, want 1", pgn, len(games))
+			continue
+		}
+		game := games[0]
+
+		if event, _ := game.Tag("Event"); event != "round trip" {
+			t.Errorf("%s\nEvent is %q", pgn, event)
+		}
+		if variant, _ := game.Tag("Variant"); variant != "bullet hell" {
+			t.Errorf("%s\nVariant is %q", pgn, variant)
+		}
+		if fen := FormatFEN(game.Root.Position); fen != tc.fen {
+			t.Errorf("%s\nstarts from %s, want %s", pgn, fen, tc.fen)
+		}
+		if game.Result != chess.ResultOngoing {
+			t.Errorf("%s\nresult %s", pgn, game.Result)
+		}
+
+		line := mainLine(game)
+		moves := 0
+		for _, node := range line {
+			if !node.IsNull() {
+				if node.Move != g.Moves[moves] {
+					t.Errorf("%s\nmove %d is %s, want %s", pgn, moves, FormatUCI(node.Move), FormatUCI(g.Moves[moves]))
+				}
+				moves++
+			}
+		}
+		if moves != len(g.Moves) {
+			t.Errorf("%s\n%d moves came back, want %d", pgn, moves, len(g.Moves))
+		}
+		end := game.Root.Position
+		if len(line) > 0 {
+			end = line[len(line)-1].Position
+		}
+		if fen, want := FormatFEN(end), FormatFEN(g.Position); fen != want {
+			t.Errorf("%s\nends at %s, want %s", pgn, fen, want)
+		}
+
+		// and writing it out again from what was read gives the same pgn
+		again := chess.NewGame(game.Root.Position.Copy())
+		for _, node := range line {
+			if node.CommentBefore != "" {
+				again.Comment(node.CommentBefore)
+			}
+			if node.IsNull() {
+				again.Pass()
+			} else if err := again.Play(node.Move); err != nil {
+				t.Fatal(err)
+			}
+			// what's left of a removal's comment gets written again by RemovePiece, these tests don't
+			// comment on the same move a piece is taken off after
+			removed := removals(node)
+			for _, square := range removed {
+				again.RemovePiece(square)
+			}
+			if node.Comment != "" && len(removed) == 0 {
+				again.Comment(node.Comment)
+			}
+		}
+		if repeat := FormatPGN(again, tags, chess.ResultOngoing, ""); repeat != pgn {
+			t.Errorf("wrote\n%s\nthen\n%s", pgn, repeat)
+		}
+	}
+}
+
+// squares that had a piece before the node's move was played and after it, but are empty in its position,
+// in board order. only right for the removals these tests make, which never take what just moved
+func removals(node *PGNNode) []chess.Square {
+	after := node.Parent.Position.Copy()
+	if node.IsNull() {
+		after.Pass()
+	} else if err := after.MakeMove(node.Move); err != nil {
+		return nil
+	}
+	squares := make([]chess.Square, 0)
+	for sq := chess.Square(0); sq < 64; sq++ {
+		if !after.Board[sq].IsEmpty() && node.Position.Board[sq].IsEmpty() {
+			squares = append(squares, sq)
+		}
+	}
+	return squares
+}
+
+func TestParsePGN(t *testing.T) {
+	games, err := ParsePGN(`[Event "first"]
+[Result "1/2-1/2"]
+
+; a line comment
+1. e4 e5 $1 (1... c5 {the sicilian} 2. Nf3 (2. c3) d6) 2. Nf3 {before} Nc6!? 1/2-1/2
+
+[Event "second"]
+[SetUp "1"]
+[FEN "7k/8/8/8/8/8/8/K5R1 w - - 0 1"]
+
+1. Rg7 {[%remove g7] taken straight back} Kh7 *
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 2 {
+		t.Fatalf("read %d games, want 2", len(games))
+	}
+
+	first := games[0]
+	if first.Result != chess.ResultDraw {
+		t.Errorf("first game's result is %s", first.Result)
+	}
+	line := mainLine(first)
+	sans := make([]string, len(line))
+	for i, node := range line {
+		sans[i] = node.SAN
+	}
+	if got := strings.Join(sans, " "); got != "e4 e5 Nf3 Nc6!?" {
+		t.Errorf("main line is %s", got)
+	}
+	if nags := line[1].NAGs; len(nags) != 1 || nags[0] != 1 {
+		t.Errorf("e5 has NAGs %v, want [1]", nags)
+	}
+	if line[2].Comment != "before" {
+		t.Errorf("Nf3 has comment %q", line[2].Comment)
+	}
+	if n, white := line[3].MoveNumber(); n != 2 || white {
+		t.Errorf("Nc6 is numbered %d white %v, want 2 black", n, white)
+	}
+
+	sicilian := line[0].Children
+	if len(sicilian) != 2 || sicilian[1].SAN != "c5" || sicilian[1].Comment != "the sicilian" {
+		t.Fatalf("e4 should have e5 and c5 after it")
+	}
+	nf3 := sicilian[1].Children
+	if len(nf3) != 2 || nf3[0].SAN != "Nf3" || nf3[1].SAN != "c3" {
+		t.Fatalf("c5 should have Nf3 and c3 after it")
+	}
+	if len(nf3[0].Children) != 1 || nf3[0].Children[0].SAN != "d6" {
+		t.Errorf("Nf3 should have d6 after it")
+	}
+
+	second := games[1]
+	if second.Result != chess.ResultOngoing {
+		t.Errorf("second game's result is %s", second.Result)
+	}
+	line = mainLine(second)
+	if len(line) != 2 {
+		t.Fatalf("second game has %d moves, want 2", len(line))
+	}
+	if line[0].Comment != "taken straight back" {
+		t.Errorf("Rg7 has comment %q", line[0].Comment)
+	}
+	if fen := FormatFEN(line[1].Position); fen != "8/7k/8/8/8/8/8/K7 w - - 2 2" {
+		t.Errorf("second game ends at %s", fen)
+	}
+}
+
+func TestParsePGNErrors(t *testing.T) {
+	for _, tc := range []struct {
+		pgn          string
+		line, column int
+	}{
+		{"1. e4 e5 2. Ke3 *", 1, 13},
+		{"1. e4 e5\n2. Nf3 Nf6 3. Nc3 Nc6 4. Nd5 Nxd5 5. Qe2 Nf6 6. Nxd7", 2, 49},
+		{"1. e4 (1. d4 d5 *", 1, 17},
+		{"1. e4 e5 ) *", 1, 10},
+		{"(1. e4) *", 1, 1},
+		{"1. e4 () *", 1, 8},
+		{"$1 1. e4 *", 1, 1},
+		{"[Event \"x\"\n1. e4 *", 2, 1},
+		{"[FEN \"not a fen\"]\n1. e4 *", 1, 1},
+		{"1. e4 {[%remove e9]} *", 1, 7},
+		{"1. e4 {[%remove e4} *", 1, 7},
+		{"1. e4 {[%remove e3]} *", 1, 7},
+		{"1. e4 (1... e5) *", 1, 13},
+		{"1. Nbd2 *", 1, 4},
+		{"1. e4 {never closed", 1, 7},
+	} {
+		_, err := ParsePGN(tc.pgn)
+		if err == nil {
+			t.Errorf("%q read without an error", tc.pgn)
+			continue
+		}
+		pgnErr, ok := err.(*PGNError)
+		if !ok {
+			t.Errorf("%q: %T %s, want a *PGNError", tc.pgn, err, err)
+			continue
+		}
+		if pgnErr.Line != tc.line || pgnErr.Column != tc.column {
+			t.Errorf("%q: error %s, want it at %d:%d", tc.pgn, err, tc.line, tc.column)
+		}
+	}
+}
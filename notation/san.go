@@ -0,0 +1,235 @@
+package notation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+)
+
+// written for a skipped turn, in SAN, LAN and pgn
+const NullMoveSAN = "--"
+
+// spelled out after an en passant capture by some older books and programs, "exd6 e.p."
+const enPassantSuffix = "e.p."
+
+// standard algebraic notation for a move about to be played from p, e.g. Nbd7, exd6, O-O, e8=Q#
+// the move is assumed to be legal for whichever side's piece is on the from square
+func FormatSAN(p *chess.Position, m chess.Move) string {
+	if IsNullMove(m) {
+		return NullMoveSAN
+	}
+	piece := p.PieceAt(m.From)
+	if castling, ok := castlingText(p, m); ok {
+		return castling + checkSuffix(p, m)
+	}
+
+	var b strings.Builder
+	capture := p.CaptureSquare(m) != chess.NoSquare
+	if piece.Type == chess.Pawn {
+		if capture {
+			b.WriteByte(byte('a' + m.From.File()))
+		}
+	} else {
+		b.WriteString(pieceLetters[piece.Type])
+		b.WriteString(sanDisambiguation(p, m, piece))
+	}
+	if capture {
+		b.WriteByte('x')
+	}
+	b.WriteString(FormatSquare(m.To))
+	if m.Promotion != chess.NoPieceType {
+		b.WriteByte('=')
+		b.WriteString(pieceLetters[m.Promotion])
+	}
+	b.WriteString(checkSuffix(p, m))
+	return b.String()
+}
+
+// just enough of the from square to tell the move apart from the same kind of piece going to the same square:
+// the file if that does it, otherwise the rank, otherwise both
+func sanDisambiguation(p *chess.Position, m chess.Move, piece chess.Piece) string {
+	ambiguous, sameFile, sameRank := false, false, false
+	for sq := chess.Square(0); sq < 64; sq++ {
+		if sq == m.From || p.Board[sq] != piece {
+			continue
+		}
+		for _, other := range p.MovesFrom(sq) {
+			if other.To != m.To {
+				continue
+			}
+			ambiguous = true
+			sameFile = sameFile || sq.File() == m.From.File()
+			sameRank = sameRank || sq.Rank() == m.From.Rank()
+			break
+		}
+	}
+	from := FormatSquare(m.From)
+	switch {
+	case !ambiguous:
+		return ""
+	case !sameFile:
+		return from[:1]
+	case !sameRank:
+		return from[1:]
+	}
+	return from
+}
+
+// O-O or O-O-O if the move is the king castling, SAN and LAN both write it that way
+func castlingText(p *chess.Position, m chess.Move) (string, bool) {
+	rookMove, ok := p.CastlingRookMove(m)
+	if !ok {
+		return "", false
+	}
+	if rookMove.From.File() == 7 {
+		return "O-O", true
+	}
+	return "O-O-O", true
+}
+
+// + or # if the move gives check or mate
+func checkSuffix(p *chess.Position, m chess.Move) string {
+	color := p.PieceAt(m.From).Color
+	after := p.Copy()
+	if err := after.MakeMove(m); err != nil || !after.InCheck(color.Other()) {
+		return ""
+	}
+	if len(after.LegalMoves()) == 0 {
+		return "#"
+	}
+	return "+"
+}
+
+// check marks, annotations (! ?) and e.p. don't change which move it is, so they're taken off before parsing
+func trimMoveSuffixes(text string) (string, bool) {
+	text = strings.TrimRight(strings.TrimSpace(text), "+#!?")
+	enPassant := strings.HasSuffix(text, enPassantSuffix)
+	if enPassant {
+		text = strings.TrimRight(strings.TrimSpace(strings.TrimSuffix(text, enPassantSuffix)), "+#!?")
+	}
+	return text, enPassant
+}
+
+// reads O-O or O-O-O (or 0-0, 0-0-0) for the side to move, ok is false if it isn't castling at all
+func parseCastling(p *chess.Position, text string) (chess.Move, bool, error) {
+	file := 6
+	switch text {
+	case "O-O", "0-0":
+	case "O-O-O", "0-0-0":
+		file = 2
+	default:
+		return chess.Move{}, false, nil
+	}
+	king := p.KingSquare(p.SideToMove)
+	move := chess.Move{From: king, To: chess.NewSquare(file, king.Rank())}
+	if _, ok := p.CastlingRookMove(move); !ok || !p.IsLegal(move) {
+		return chess.Move{}, true, fmt.Errorf("%s can't castle that way", p.SideToMove)
+	}
+	return move, true, nil
+}
+
+// reads a move in SAN for the side to move. check marks, annotations and e.p. are allowed but not needed,
+// 0-0 works for castling, the = before a promotion can be left out, and the from square can be given more
+// fully than it needs to be. NullMoveSAN comes back as NullMove
+func ParseSAN(p *chess.Position, san string) (chess.Move, error) {
+	text, enPassant := trimMoveSuffixes(san)
+	if text == NullMoveSAN {
+		return NullMove, nil
+	}
+	if move, ok, err := parseCastling(p, text); ok {
+		if err != nil {
+			return chess.Move{}, fmt.Errorf("%s: %s", san, err)
+		}
+		return move, nil
+	}
+
+	pieceType := chess.Pawn
+	if len(text) > 0 {
+		if t := pieceTypeFromLetter(text[0]); t != chess.NoPieceType {
+			pieceType = t
+			text = text[1:]
+		}
+	}
+
+	promotion := chess.NoPieceType
+	if i := strings.IndexByte(text, '='); i >= 0 {
+		text, promotion = text[:i], promotionFromLetter(text[i+1:])
+		if promotion == chess.NoPieceType {
+			return chess.Move{}, fmt.Errorf("%s: bad promotion", san)
+		}
+	} else if pieceType == chess.Pawn && len(text) > 0 {
+		// some programs leave the = out
+		if t := promotionFromLetter(text[len(text)-1:]); t != chess.NoPieceType {
+			text, promotion = text[:len(text)-1], t
+		}
+	}
+
+	capture := false
+	if i := strings.IndexByte(text, 'x'); i >= 0 {
+		text, capture = text[:i]+text[i+1:], true
+	}
+	if len(text) < 2 || len(text) > 4 {
+		return chess.Move{}, fmt.Errorf("%s: not a move", san)
+	}
+	to, err := ParseSquare(text[len(text)-2:])
+	if err != nil {
+		return chess.Move{}, fmt.Errorf("%s: not a move", san)
+	}
+	fromFile, fromRank := -1, -1
+	for _, c := range text[:len(text)-2] {
+		switch {
+		case c >= 'a' && c <= 'h' && fromFile < 0 && fromRank < 0:
+			fromFile = int(c - 'a')
+		case c >= '1' && c <= '8' && fromRank < 0:
+			fromRank = int(c - '1')
+		default:
+			return chess.Move{}, fmt.Errorf("%s: not a move", san)
+		}
+	}
+
+	matches := make([]chess.Move, 0, 1)
+	// moves that would match if the promotion piece was right
+	promotions := 0
+	for _, move := range p.LegalMoves() {
+		if p.Board[move.From].Type != pieceType || move.To != to {
+			continue
+		}
+		if (fromFile >= 0 && move.From.File() != fromFile) || (fromRank >= 0 && move.From.Rank() != fromRank) {
+			continue
+		}
+		if move.Promotion != promotion {
+			promotions++
+			continue
+		}
+		matches = append(matches, move)
+	}
+	switch len(matches) {
+	case 0:
+		if promotions > 0 && promotion == chess.NoPieceType {
+			return chess.Move{}, fmt.Errorf("%s: promotion needs a piece", san)
+		}
+		return chess.Move{}, fmt.Errorf("%s: no legal move matches for %s", san, p.SideToMove)
+	case 1:
+	default:
+		return chess.Move{}, fmt.Errorf("%s: ambiguous, more than one %s can go to %s", san, pieceType, FormatSquare(to))
+	}
+
+	move := matches[0]
+	if err := checkCapture(p, move, capture, enPassant); err != nil {
+		return chess.Move{}, fmt.Errorf("%s: %s", san, err)
+	}
+	return move, nil
+}
+
+// an x is only allowed on a capture and e.p. only on an en passant capture, leaving x out is let go
+func checkCapture(p *chess.Position, m chess.Move, capture, enPassant bool) error {
+	captureSquare := p.CaptureSquare(m)
+	if capture && captureSquare == chess.NoSquare {
+		return fmt.Errorf("nothing to take on %s", FormatSquare(m.To))
+	}
+	if enPassant && (captureSquare == chess.NoSquare || captureSquare == m.To) {
+		return fmt.Errorf("not an en passant capture")
+	}
+	return nil
+}
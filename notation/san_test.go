@@ -0,0 +1,185 @@
+package notation
+
+import (
+	"testing"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+)
+
+const (
+	// knights on b6 and e5 can both take on d7, which checks the king on f8
+	twoKnightsFEN = "5k2/3p4/1N6/4N3/8/8/8/4K3 w - - 0 1"
+	// black just played f7f5, so exf6 is en passant. dxe6 isn't
+	enPassantFEN = "rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3"
+	// four knights around e4 and e6, two on each file and rank
+	fourKnightsFEN = "1k6/8/8/3N1N2/8/3N1N2/8/1K6 w - - 0 1"
+	// queens on a1, a5 and h5 can all get to e5, which checks the king on b8
+	threeQueensFEN = "1k6/8/8/Q6Q/8/8/8/Q3K3 w - - 0 1"
+	promotionFEN   = "7k/P7/8/8/8/8/8/K7 w - - 0 1"
+	backRankFEN    = "6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1"
+	castlingFEN    = "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1"
+)
+
+func TestFormatSAN(t *testing.T) {
+	for _, tc := range []struct {
+		fen string
+		uci string
+		san string
+	}{
+		{StartingFEN, "e2e4", "e4"},
+		{StartingFEN, "g1f3", "Nf3"},
+		{twoKnightsFEN, "b6d7", "Nbxd7+"},
+		{twoKnightsFEN, "e5d7", "Nexd7+"},
+		{twoKnightsFEN, "e5f7", "Nf7"},
+		{enPassantFEN, "e5f6", "exf6"},
+		{enPassantFEN, "e5e6", "e6"},
+		{fourKnightsFEN, "d3e5", "Nde5"},
+		{fourKnightsFEN, "f5e3", "Nfe3"},
+		{fourKnightsFEN, "d5b4", "N5b4"},
+		{fourKnightsFEN, "d5c7", "Nc7"},
+		{threeQueensFEN, "a1e5", "Q1e5+"},
+		{threeQueensFEN, "a5e5", "Qa5e5+"},
+		{threeQueensFEN, "h5e5", "Qhe5+"},
+		{promotionFEN, "a7a8q", "a8=Q+"},
+		{promotionFEN, "a7a8r", "a8=R+"},
+		{promotionFEN, "a7a8n", "a8=N"},
+		{backRankFEN, "a1a8", "Ra8#"},
+		{castlingFEN, "e1g1", "O-O"},
+		{castlingFEN, "e1c1", "O-O-O"},
+		{"r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 0 1", "e8c8", "O-O-O"},
+		{"3k4/8/8/8/8/8/8/R3K3 w Q - 0 1", "e1c1", "O-O-O+"},
+	} {
+		p := mustParseFEN(t, tc.fen)
+		move, err := ParseUCIMove(p, tc.uci)
+		if err != nil {
+			t.Errorf("%s: %s", tc.fen, err)
+			continue
+		}
+		if san := FormatSAN(p, move); san != tc.san {
+			t.Errorf("%s %s formatted as %s, want %s", tc.fen, tc.uci, san, tc.san)
+		}
+	}
+
+	if san := FormatSAN(chess.NewStartingPosition(), NullMove); san != NullMoveSAN {
+		t.Errorf("null move formatted as %s", san)
+	}
+}
+
+func TestParseSAN(t *testing.T) {
+	for _, tc := range []struct {
+		fen string
+		san string
+		uci string
+	}{
+		{StartingFEN, "e4", "e2e4"},
+		{StartingFEN, "Nf3", "g1f3"},
+		{StartingFEN, "N1f3", "g1f3"},
+		{StartingFEN, "Ng1f3", "g1f3"},
+		{StartingFEN, "Nf3!?", "g1f3"},
+		{StartingFEN, "--", NullMoveUCI},
+		{twoKnightsFEN, "Nbxd7+", "b6d7"},
+		{twoKnightsFEN, "Nbd7", "b6d7"},
+		{twoKnightsFEN, "N6xd7", "b6d7"},
+		{twoKnightsFEN, "Nexd7", "e5d7"},
+		{enPassantFEN, "exf6", "e5f6"},
+		{enPassantFEN, "exf6 e.p.", "e5f6"},
+		{enPassantFEN, "exf6e.p.", "e5f6"},
+		{enPassantFEN, "ef6", "e5f6"},
+		{fourKnightsFEN, "Nde5", "d3e5"},
+		{fourKnightsFEN, "Nf5e3", "f5e3"},
+		{fourKnightsFEN, "Nde3", "d5e3"},
+		{fourKnightsFEN, "N5b4", "d5b4"},
+		{threeQueensFEN, "Q1e5+", "a1e5"},
+		{threeQueensFEN, "Qa5e5", "a5e5"},
+		{threeQueensFEN, "Qhe5", "h5e5"},
+		{promotionFEN, "a8=Q+", "a7a8q"},
+		{promotionFEN, "a8Q", "a7a8q"},
+		{promotionFEN, "a8=N", "a7a8n"},
+		{backRankFEN, "Ra8#", "a1a8"},
+		{castlingFEN, "O-O", "e1g1"},
+		{castlingFEN, "0-0-0", "e1c1"},
+		{"r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 0 1", "O-O-O", "e8c8"},
+	} {
+		p := mustParseFEN(t, tc.fen)
+		move, err := ParseSAN(p, tc.san)
+		if err != nil {
+			t.Errorf("%s: %s", tc.fen, err)
+			continue
+		}
+		if uci := FormatUCI(move); uci != tc.uci {
+			t.Errorf("%s %s parsed to %s, want %s", tc.fen, tc.san, uci, tc.uci)
+		}
+	}
+}
+
+func TestParseSANErrors(t *testing.T) {
+	for _, tc := range []struct {
+		fen string
+		san string
+	}{
+		// ambiguous
+		{twoKnightsFEN, "Nxd7"},
+		{fourKnightsFEN, "Ne5"},
+		{fourKnightsFEN, "N3e5"},
+		{fourKnightsFEN, "Nb4"},
+		{fourKnightsFEN, "Ne3"},
+		{threeQueensFEN, "Qe5"},
+		{threeQueensFEN, "Qae5"},
+		{threeQueensFEN, "Q5e5"},
+		// illegal
+		{StartingFEN, "e5"},
+		{StartingFEN, "Nf4"},
+		{StartingFEN, "Ke2"},
+		{StartingFEN, "O-O"},
+		{StartingFEN, "exd3"},
+		{enPassantFEN, "dxe6"},
+		{enPassantFEN, "e6 e.p."},
+		{twoKnightsFEN, "Nbxc8"},
+		{"4k3/8/8/8/8/8/8/R3K2r w Q - 0 1", "O-O-O"},
+		// no piece for the promotion, or one a pawn can't become
+		{promotionFEN, "a8"},
+		{promotionFEN, "a8=K"},
+		{StartingFEN, "e4=Q"},
+		// not a move at all
+		{StartingFEN, ""},
+		{StartingFEN, "x"},
+		{StartingFEN, "Nz3"},
+		{StartingFEN, "Ng1g1f3"},
+	} {
+		p := mustParseFEN(t, tc.fen)
+		if move, err := ParseSAN(p, tc.san); err == nil {
+			t.Errorf("%s %q parsed to %s, want an error", tc.fen, tc.san, FormatUCI(move))
+		}
+	}
+}
+
+// every legal move two plies deep from a few busy positions comes back as itself
+func TestSANRoundTrip(t *testing.T) {
+	for _, fen := range []string{StartingFEN, twoKnightsFEN, enPassantFEN, fourKnightsFEN, threeQueensFEN, castlingFEN,
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1"} {
+		p := mustParseFEN(t, fen)
+		for _, first := range p.LegalMoves() {
+			roundTripSAN(t, p, first)
+			after := p.Copy()
+			if err := after.MakeMove(first); err != nil {
+				t.Fatal(err)
+			}
+			for _, second := range after.LegalMoves() {
+				roundTripSAN(t, after, second)
+			}
+		}
+	}
+}
+
+func roundTripSAN(t *testing.T, p *chess.Position, move chess.Move) {
+	t.Helper()
+	san := FormatSAN(p, move)
+	parsed, err := ParseSAN(p, san)
+	if err != nil {
+		t.Errorf("%s: %s came back with %s", FormatFEN(p), san, err)
+		return
+	}
+	if parsed != move {
+		t.Errorf("%s: %s parsed to %s, want %s", FormatFEN(p), san, FormatUCI(parsed), FormatUCI(move))
+	}
+}
@@ -0,0 +1,44 @@
+package notation
+
+// text formats for the rules core: squares, moves (SAN, LAN, UCI), positions (FEN) and whole games (PGN)
+// everything that has to know what's legal to read or write a move takes the position it's played from
+
+import (
+	"fmt"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+)
+
+// upper case, pawns don't get one in SAN or LAN
+var pieceLetters = map[chess.PieceType]string{
+	chess.Knight: "N",
+	chess.Bishop: "B",
+	chess.Rook:   "R",
+	chess.Queen:  "Q",
+	chess.King:   "K",
+}
+
+func pieceTypeFromLetter(letter byte) chess.PieceType {
+	for t, l := range pieceLetters {
+		if l[0] == letter {
+			return t
+		}
+	}
+	return chess.NoPieceType
+}
+
+// file then rank, "e4"
+func ParseSquare(name string) (chess.Square, error) {
+	if len(name) != 2 || name[0] < 'a' || name[0] > 'h' || name[1] < '1' || name[1] > '8' {
+		return chess.NoSquare, fmt.Errorf("bad square %q", name)
+	}
+	return chess.NewSquare(int(name[0]-'a'), int(name[1]-'1')), nil
+}
+
+// "-" for NoSquare, the way FEN writes a missing en passant square
+func FormatSquare(square chess.Square) string {
+	if !square.Valid() {
+		return "-"
+	}
+	return string([]byte{byte('a' + square.File()), byte('1' + square.Rank())})
+}
@@ -0,0 +1,61 @@
+package notation
+
+import (
+	"testing"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+)
+
+func TestSquares(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		file, rank int
+	}{
+		{"a1", 0, 0},
+		{"h1", 7, 0},
+		{"e4", 4, 3},
+		{"a8", 0, 7},
+		{"h8", 7, 7},
+	} {
+		square, err := ParseSquare(tc.name)
+		if err != nil {
+			t.Errorf("%s: %s", tc.name, err)
+			continue
+		}
+		if want := chess.NewSquare(tc.file, tc.rank); square != want {
+			t.Errorf("%s parsed to %v, want %v", tc.name, square, want)
+		}
+		if name := FormatSquare(square); name != tc.name {
+			t.Errorf("%s formatted as %s", tc.name, name)
+		}
+	}
+
+	for _, name := range []string{"", "e", "e44", "i1", "a0", "a9", "E4", "4e", "-"} {
+		if square, err := ParseSquare(name); err == nil {
+			t.Errorf("%q parsed to %v, want an error", name, square)
+		}
+	}
+
+	if name := FormatSquare(chess.NoSquare); name != "-" {
+		t.Errorf("NoSquare formatted as %q, want -", name)
+	}
+}
+
+// position from a fen the test knows is good
+func mustParseFEN(t *testing.T, fen string) *chess.Position {
+	t.Helper()
+	p, err := ParseFEN(fen)
+	if err != nil {
+		t.Fatalf("%s: %s", fen, err)
+	}
+	return p
+}
+
+func mustParseSquare(t *testing.T, name string) chess.Square {
+	t.Helper()
+	square, err := ParseSquare(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return square
+}
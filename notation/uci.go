@@ -0,0 +1,81 @@
+package notation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+)
+
+// what engines send for a move that isn't one
+const NullMoveUCI = "0000"
+
+// stands in for a skipped turn where a move is expected
+var NullMove = chess.Move{From: chess.NoSquare, To: chess.NoSquare}
+
+func IsNullMove(m chess.Move) bool {
+	return m.From == chess.NoSquare && m.To == chess.NoSquare
+}
+
+// the from and to squares with a lower case promotion letter on the end, "e2e4", "e7e8q"
+// castling is the king's move, e1g1. nothing here knows about the position, so the move might not be legal
+func ParseUCI(text string) (chess.Move, error) {
+	if text == NullMoveUCI {
+		return NullMove, nil
+	}
+	if len(text) != 4 && len(text) != 5 {
+		return chess.Move{}, fmt.Errorf("%s: not a uci move", text)
+	}
+	from, err := ParseSquare(text[0:2])
+	if err != nil {
+		return chess.Move{}, fmt.Errorf("%s: %s", text, err)
+	}
+	to, err := ParseSquare(text[2:4])
+	if err != nil {
+		return chess.Move{}, fmt.Errorf("%s: %s", text, err)
+	}
+	move := chess.Move{From: from, To: to}
+	if len(text) == 5 {
+		move.Promotion = promotionFromLetter(strings.ToUpper(text[4:]))
+		if move.Promotion == chess.NoPieceType {
+			return chess.Move{}, fmt.Errorf("%s: bad promotion %q", text, text[4])
+		}
+	}
+	if from == to {
+		return chess.Move{}, fmt.Errorf("%s: from and to are the same square", text)
+	}
+	return move, nil
+}
+
+// like ParseUCI, but the move has to be legal for whichever piece is on the from square
+func ParseUCIMove(p *chess.Position, text string) (chess.Move, error) {
+	move, err := ParseUCI(text)
+	if err != nil {
+		return chess.Move{}, err
+	}
+	if IsNullMove(move) || !p.IsLegal(move) {
+		return chess.Move{}, fmt.Errorf("%s: not a legal move", text)
+	}
+	return move, nil
+}
+
+func FormatUCI(m chess.Move) string {
+	if IsNullMove(m) {
+		return NullMoveUCI
+	}
+	s := FormatSquare(m.From) + FormatSquare(m.To)
+	if m.Promotion != chess.NoPieceType {
+		s += strings.ToLower(pieceLetters[m.Promotion])
+	}
+	return s
+}
+
+// only the pieces a pawn can become
+func promotionFromLetter(letter string) chess.PieceType {
+	for _, t := range chess.PromotionPieces {
+		if pieceLetters[t] == letter {
+			return t
+		}
+	}
+	return chess.NoPieceType
+}
@@ -0,0 +1,65 @@
+package notation
+
+import (
+	"testing"
+
+	"github.com/val-is/bullet-hell-chess/chess"
+)
+
+func TestUCI(t *testing.T) {
+	for _, tc := range []struct {
+		text      string
+		from, to  string
+		promotion chess.PieceType
+	}{
+		{"e2e4", "e2", "e4", chess.NoPieceType},
+		{"g8f6", "g8", "f6", chess.NoPieceType},
+		{"e1g1", "e1", "g1", chess.NoPieceType},
+		{"e7e8q", "e7", "e8", chess.Queen},
+		{"a2a1n", "a2", "a1", chess.Knight},
+		{"b7a8r", "b7", "a8", chess.Rook},
+		{"h7h8b", "h7", "h8", chess.Bishop},
+	} {
+		move, err := ParseUCI(tc.text)
+		if err != nil {
+			t.Errorf("%s: %s", tc.text, err)
+			continue
+		}
+		want := chess.Move{From: mustParseSquare(t, tc.from), To: mustParseSquare(t, tc.to), Promotion: tc.promotion}
+		if move != want {
+			t.Errorf("%s parsed to %+v, want %+v", tc.text, move, want)
+		}
+		if text := FormatUCI(move); text != tc.text {
+			t.Errorf("%s formatted as %s", tc.text, text)
+		}
+	}
+
+	move, err := ParseUCI(NullMoveUCI)
+	if err != nil || !IsNullMove(move) {
+		t.Errorf("%s parsed to %+v, %v, want the null move", NullMoveUCI, move, err)
+	}
+	if text := FormatUCI(NullMove); text != NullMoveUCI {
+		t.Errorf("null move formatted as %s", text)
+	}
+
+	for _, text := range []string{"", "e2", "e2e", "e2e4qq", "e2e9", "z2e4", "e7e8k", "e7e8p", "e2e2"} {
+		if move, err := ParseUCI(text); err == nil {
+			t.Errorf("%q parsed to %+v, want an error", text, move)
+		}
+	}
+}
+
+// the move only has to be legal for the piece on the from square, in real-time either side can move
+func TestUCIMove(t *testing.T) {
+	p := chess.NewStartingPosition()
+	for _, text := range []string{"e2e4", "g1f3", "b1c3", "e7e5"} {
+		if _, err := ParseUCIMove(p, text); err != nil {
+			t.Errorf("%s: %s", text, err)
+		}
+	}
+	for _, text := range []string{"e2e5", "e7e4", "e1g1", "d1d3", NullMoveUCI} {
+		if move, err := ParseUCIMove(p, text); err == nil {
+			t.Errorf("%s parsed to %+v from the start, want an error", text, move)
+		}
+	}
+}
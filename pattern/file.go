@@ -0,0 +1,364 @@
+package pattern
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// a named set of emitters that all fire together from the same spot
+type File struct {
+	Name     string    `json:"name"`
+	Emitters []Emitter `json:"emitters"`
+}
+
+// problem with a pattern file, pointing at where in the file it is
+type Error struct {
+	File    string
+	Line    int
+	Column  int
+	Field   string
+	Message string
+}
+
+func (e Error) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s: %s", e.File, e.Line, e.Column, e.Field, e.Message)
+}
+
+// everything wrong with a file, so designers can fix it all in one go
+type Errors []Error
+
+func (e Errors) Error() string {
+	lines := make([]string, 0, len(e))
+	for _, err := range e {
+		lines = append(lines, err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+var fileFields = map[string]bool{"name": true, "emitters": true}
+
+var emitterFields = map[string]bool{
+	"type": true, "count": true, "speed": true, "acceleration": true, "radius": true,
+	"angle": true, "angleStep": true, "spread": true, "delay": true, "interval": true,
+	"repeat": true, "lifetime": true, "children": true,
+}
+
+// parses and checks a pattern file, filename is only used for error messages
+func Parse(filename string, data []byte) (File, error) {
+	file := File{}
+
+	// find where every field lives first, syntax errors come out of this too
+	locations, err := locateJSONFields(data)
+	if err == nil {
+		err = json.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return file, Errors{decodeError(filename, data, locations, err)}
+	}
+
+	problems := make(Errors, 0)
+	addProblem := func(field, message string) {
+		line, col := locations.find(field)
+		problems = append(problems, Error{filename, line, col, field, message})
+	}
+
+	// anything misspelt would otherwise just silently be zero
+	for _, field := range locations.fields() {
+		name := field[strings.LastIndex(field, ".")+1:]
+		known := emitterFields[name]
+		if !strings.Contains(field, ".") {
+			known = fileFields[name]
+		}
+		if !known {
+			addProblem(field, "unknown field")
+		}
+	}
+	if file.Name == "" {
+		addProblem("name", "pattern needs a name")
+	}
+	if len(file.Emitters) == 0 {
+		addProblem("emitters", "pattern needs at least one emitter")
+	}
+	for k, emitter := range file.Emitters {
+		for _, problem := range emitter.Problems() {
+			addProblem(fmt.Sprintf("emitters[%d].%s", k, problem.Field), problem.Message)
+		}
+		// children go when their bullet does, but nothing would ever take away a top level emitter
+		if emitter.Repeat == 0 {
+			addProblem(fmt.Sprintf("emitters[%d].repeat", k), "top level emitters need a number of shots, 0 would never finish")
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.SliceStable(problems, func(i, j int) bool {
+			if problems[i].Line != problems[j].Line {
+				return problems[i].Line < problems[j].Line
+			}
+			return problems[i].Column < problems[j].Column
+		})
+		return file, problems
+	}
+	return file, nil
+}
+
+// puts a decoding error where it happened in the file, in terms someone editing json would use
+func decodeError(filename string, data []byte, locations jsonLocations, err error) Error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr) && syntaxErr.Error() != "unexpected end of JSON input":
+		// the offset counts the character that broke it, so step back onto it
+		line, col := offsetToLineColumn(data, syntaxErr.Offset-1)
+		return Error{filename, line, col, "", syntaxErr.Error()}
+	case syntaxErr != nil, errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		line, col := offsetToLineColumn(data, int64(len(data)))
+		return Error{filename, line, col, "", "unexpected end of file"}
+	case errors.As(err, &typeErr):
+		// the offset is just past the bad value, so point at where the value starts instead
+		field := jsonFieldPath(typeErr.Field)
+		line, col := offsetToLineColumn(data, typeErr.Offset)
+		if offset, ok := locations.values[field]; ok {
+			line, col = offsetToLineColumn(data, offset)
+		}
+		return Error{filename, line, col, field,
+			fmt.Sprintf("expected %s, got %s", describeJSONType(typeErr.Type), describeJSONValue(typeErr.Value))}
+	}
+	return Error{filename, 1, 1, "", err.Error()}
+}
+
+func Load(filename string) (File, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return File{}, err
+	}
+	return Parse(filename, data)
+}
+
+// every .json file in the directory, keyed by pattern name
+func LoadDir(dir string) (map[string]File, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]File)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		file, err := Load(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := files[file.Name]; exists {
+			return nil, fmt.Errorf("%s: pattern name %s is already used by another file", entry.Name(), file.Name)
+		}
+		files[file.Name] = file
+	}
+	return files, nil
+}
+
+// checks every pattern file in a directory without stopping at the first bad one
+func ValidateDir(dir string) []error {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return []error{err}
+	}
+	problems := make([]error, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		if _, err := Load(filepath.Join(dir, entry.Name())); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	return problems
+}
+
+// encoding/json writes paths like "emitters.0.count", this turns them into "emitters[0].count" to match
+// everything else
+func jsonFieldPath(field string) string {
+	path := ""
+	for _, part := range strings.Split(field, ".") {
+		switch {
+		case part == "":
+		case isIndex(part):
+			path += "[" + part + "]"
+		case path == "":
+			path = part
+		default:
+			path += "." + part
+		}
+	}
+	return path
+}
+
+func isIndex(part string) bool {
+	_, err := strconv.Atoi(part)
+	return err == nil
+}
+
+// json's names for things rather than go's, it's designers reading these
+func describeJSONType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a whole number"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	}
+	return t.Kind().String()
+}
+
+// the decoder's description of what it found, e.g. "array" or "number 1.5"
+func describeJSONValue(value string) string {
+	switch {
+	case value == "object", value == "array":
+		return "an " + value
+	case value == "string", value == "number":
+		return "a " + value
+	case value == "bool":
+		return "true or false"
+	case strings.HasPrefix(value, "number "):
+		return strings.TrimPrefix(value, "number ")
+	}
+	return value
+}
+
+// where each field's key is in a json document, by path e.g. "emitters[0].children[1].count"
+// array elements are located too, but only object keys count as fields
+type jsonLocations struct {
+	data    []byte
+	offsets map[string]int64
+	keys    map[string]bool
+	// where each field's value starts, for pointing at a value of the wrong type
+	values map[string]int64
+}
+
+func (l jsonLocations) fields() []string {
+	fields := make([]string, 0, len(l.keys))
+	for field := range l.keys {
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return l.offsets[fields[i]] < l.offsets[fields[j]]
+	})
+	return fields
+}
+
+// falls back to the closest enclosing thing that was found, e.g. a missing field points at its object
+func (l jsonLocations) find(field string) (line, column int) {
+	for field != "" {
+		if offset, ok := l.offsets[field]; ok {
+			return offsetToLineColumn(l.data, offset)
+		}
+		if cut := strings.LastIndexAny(field, ".["); cut >= 0 {
+			field = field[:cut]
+		} else {
+			field = ""
+		}
+	}
+	return 1, 1
+}
+
+func locateJSONFields(data []byte) (jsonLocations, error) {
+	locations := jsonLocations{data, make(map[string]int64), make(map[string]bool), make(map[string]int64)}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	// the decoder's offset sits just after the previous token, so skip separators to land on the next one
+	nextOffset := func() int64 {
+		offset := decoder.InputOffset()
+		for offset < int64(len(data)) && strings.ContainsRune(" \t\r\n,:", rune(data[offset])) {
+			offset++
+		}
+		return offset
+	}
+
+	var walk func(path string) error
+	walk = func(path string) error {
+		locations.values[path] = nextOffset()
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		delim, ok := token.(json.Delim)
+		if !ok {
+			return nil
+		}
+		switch delim {
+		case '{':
+			for decoder.More() {
+				offset := nextOffset()
+				key, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				field := key.(string)
+				if path != "" {
+					field = path + "." + field
+				}
+				locations.offsets[field] = offset
+				locations.keys[field] = true
+				if err := walk(field); err != nil {
+					return err
+				}
+			}
+		case '[':
+			for k := 0; decoder.More(); k++ {
+				element := fmt.Sprintf("%s[%d]", path, k)
+				locations.offsets[element] = nextOffset()
+				if err := walk(element); err != nil {
+					return err
+				}
+			}
+		}
+		// closing delimiter
+		_, err = decoder.Token()
+		return err
+	}
+
+	if err := walk(""); err != nil {
+		return locations, err
+	}
+	return locations, nil
+}
+
+// 1 indexed, like editors show
+func offsetToLineColumn(data []byte, offset int64) (line, column int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	line, column = 1, 1
+	for _, b := range data[:offset] {
+		if b == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	return line, column
+}
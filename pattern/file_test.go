@@ -0,0 +1,178 @@
+package pattern
+
+import (
+	"strings"
+	"testing"
+)
+
+const goodFile = `{
+  "name": "burst",
+  "emitters": [
+    {
+      "type": "radial",
+      "count": 8,
+      "speed": 2,
+      "radius": 3,
+      "interval": 10,
+      "repeat": 3,
+      "children": [
+        {"type": "aimed", "count": 1, "speed": 1, "radius": 2, "interval": 5}
+      ]
+    }
+  ]
+}`
+
+func TestParse(t *testing.T) {
+	file, err := Parse("burst.json", []byte(goodFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Name != "burst" || len(file.Emitters) != 1 {
+		t.Fatalf("parsed %+v", file)
+	}
+	emitter := file.Emitters[0]
+	if emitter.Type != Radial || emitter.Count != 8 || emitter.Repeat != 3 || len(emitter.Children) != 1 {
+		t.Errorf("parsed emitter %+v", emitter)
+	}
+	// children can go on forever, they go with their bullet
+	if child := emitter.Children[0]; child.Type != Aimed || child.Repeat != 0 {
+		t.Errorf("parsed child %+v", child)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		data string
+		want []string
+	}{
+		{
+			name: "syntax",
+			data: "{\n  \"name\": \"x\",\n  \"emitters\": [}\n}",
+			want: []string{"f.json:3:16: invalid character '}' looking for beginning of value"},
+		},
+		{
+			name: "truncated",
+			data: "{\n  \"name\": \"x\",\n",
+			want: []string{"f.json:3:1: unexpected end of file"},
+		},
+		{
+			name: "empty file",
+			data: "",
+			want: []string{"f.json:1:1: unexpected end of file"},
+		},
+		{
+			name: "trailing garbage",
+			data: "{\"name\": \"x\"}\n}",
+			want: []string{"f.json:2:1: invalid character '}' after top-level value"},
+		},
+		{
+			name: "root array",
+			data: `[{"name": "x"}]`,
+			want: []string{"f.json:1:1: expected an object, got an array"},
+		},
+		{
+			name: "wrong type",
+			data: "{\n  \"name\": \"x\",\n  \"emitters\": [\n    {\"type\": \"radial\", \"count\": \"8\"}\n  ]\n}",
+			want: []string{"f.json:4:33: emitters[0].count: expected a whole number, got a string"},
+		},
+		{
+			name: "fraction",
+			data: "{\"name\": \"x\", \"emitters\": [{\"type\": \"radial\", \"count\": 1.5}]}",
+			want: []string{"f.json:1:56: emitters[0].count: expected a whole number, got 1.5"},
+		},
+		{
+			name: "wrong type in a child",
+			data: "{\"name\": \"x\", \"emitters\": [{\"children\": [{}, {\"delay\": true}]}]}",
+			want: []string{"f.json:1:56: emitters[0].children[1].delay: expected a whole number, got true or false"},
+		},
+		{
+			name: "emitters not an array",
+			data: "{\"name\": \"x\", \"emitters\": {}}",
+			want: []string{"f.json:1:27: emitters: expected an array, got an object"},
+		},
+		{
+			name: "empty",
+			data: "{}",
+			want: []string{
+				"f.json:1:1: name: pattern needs a name",
+				"f.json:1:1: emitters: pattern needs at least one emitter",
+			},
+		},
+		{
+			name: "unknown field",
+			data: "{\n  \"name\": \"x\",\n  \"emiters\": []\n}",
+			want: []string{
+				"f.json:1:1: emitters: pattern needs at least one emitter",
+				"f.json:3:3: emiters: unknown field",
+			},
+		},
+		{
+			name: "negative values",
+			data: "{\n  \"name\": \"x\",\n  \"emitters\": [\n    {\n      \"type\": \"wall\",\n      \"count\": -1,\n" +
+				"      \"radius\": -2,\n      \"delay\": -3,\n      \"interval\": -4,\n      \"repeat\": -5,\n" +
+				"      \"lifetime\": -6\n    }\n  ]\n}",
+			want: []string{
+				"f.json:6:7: emitters[0].count: pattern needs at least one bullet per shot, got -1",
+				"f.json:7:7: emitters[0].radius: bullet radius must be positive, got -2",
+				"f.json:8:7: emitters[0].delay: pattern delay can't be negative, got -3",
+				"f.json:9:7: emitters[0].interval: pattern interval must be at least one tick, got -4",
+				"f.json:10:7: emitters[0].repeat: pattern repeat can't be negative, got -5",
+				"f.json:11:7: emitters[0].lifetime: bullet lifetime can't be negative, got -6",
+			},
+		},
+		{
+			name: "missing fields point at their emitter",
+			data: "{\n  \"name\": \"x\",\n  \"emitters\": [\n    {\"type\": \"spiral\"}\n  ]\n}",
+			want: []string{
+				"f.json:4:5: emitters[0].count: pattern needs at least one bullet per shot, got 0",
+				"f.json:4:5: emitters[0].radius: bullet radius must be positive, got 0",
+				"f.json:4:5: emitters[0].interval: pattern interval must be at least one tick, got 0",
+				"f.json:4:5: emitters[0].repeat: top level emitters need a number of shots, 0 would never finish",
+			},
+		},
+		{
+			name: "repeat 0 on a top level emitter",
+			data: "{\"name\": \"x\", \"emitters\": [\n" +
+				"  {\"type\": \"radial\", \"count\": 1, \"radius\": 1, \"interval\": 1, \"repeat\": 1},\n" +
+				"  {\"type\": \"radial\", \"count\": 1, \"radius\": 1, \"interval\": 1, \"repeat\": 0}\n]}",
+			want: []string{"f.json:3:62: emitters[1].repeat: top level emitters need a number of shots, 0 would never finish"},
+		},
+		{
+			name: "bad child",
+			data: "{\"name\": \"x\", \"emitters\": [\n" +
+				"  {\"type\": \"radial\", \"count\": 1, \"radius\": 1, \"interval\": 1, \"repeat\": 1, \"children\": [\n" +
+				"    {\"type\": \"ring\", \"count\": 1, \"radius\": 1, \"interval\": 1, \"speeed\": 2}\n  ]}\n]}",
+			want: []string{
+				"f.json:3:6: emitters[0].children[0].type: unknown pattern type \"ring\", expected radial, spiral, aimed or wall",
+				"f.json:3:62: emitters[0].children[0].speeed: unknown field",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse("f.json", []byte(tc.data))
+			if err == nil {
+				t.Fatal("no error")
+			}
+			if _, ok := err.(Errors); !ok {
+				t.Fatalf("error is a %T, want Errors", err)
+			}
+			if want := strings.Join(tc.want, "\n"); err.Error() != want {
+				t.Errorf("got\n%s\nwant\n%s", err, want)
+			}
+		})
+	}
+}
+
+func TestParseShippedPatterns(t *testing.T) {
+	if problems := ValidateDir("../assets/patterns"); len(problems) > 0 {
+		t.Fatal(problems)
+	}
+	files, err := LoadDir("../assets/patterns")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no patterns loaded")
+	}
+}
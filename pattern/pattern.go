@@ -0,0 +1,159 @@
+package pattern
+
+import (
+	"fmt"
+	"math"
+)
+
+// bullet patterns and the files they're written in, kept apart from the engine so they can be loaded
+// and checked without a window
+
+type Type string
+
+const (
+	// evenly spaced all the way around
+	Radial Type = "radial"
+	// like radial, but the whole thing turns a bit every shot
+	Spiral Type = "spiral"
+	// fanned out at the cursor
+	Aimed Type = "aimed"
+	// a line of bullets side by side, all heading the same way
+	Wall Type = "wall"
+)
+
+// description of what an emitter fires and how often, see assets/patterns for the file format
+// angles are in turns, speeds in pixels per tick, times in ticks
+type Emitter struct {
+	Type         Type    `json:"type"`
+	Count        int     `json:"count"`
+	Speed        float64 `json:"speed"`
+	Acceleration float64 `json:"acceleration"`
+	Radius       float64 `json:"radius"`
+	// direction of the first bullet (radial/spiral) or of travel (wall)
+	Angle float64 `json:"angle"`
+	// how far a spiral turns between shots
+	AngleStep float64 `json:"angleStep"`
+	// total arc for aimed shots, or gap in pixels between bullets in a wall
+	Spread float64 `json:"spread"`
+	// wait before the first shot
+	Delay    int `json:"delay"`
+	Interval int `json:"interval"`
+	// number of shots before the emitter goes away, 0 to keep going forever
+	// (pattern files only allow 0 on children, which go away with their bullet)
+	Repeat int `json:"repeat"`
+	// bullets vanish after this long even if they're still on screen, 0 to only go when they leave
+	Lifetime int `json:"lifetime"`
+	// patterns fired by each bullet this pattern fires, timed from when that bullet was fired
+	Children []Emitter `json:"children"`
+}
+
+// where a single bullet starts and how it moves
+type Spawn struct {
+	X, Y   float64
+	VX, VY float64
+	AX, AY float64
+}
+
+// something wrong with one field of a pattern, the field is a path like "children[0].count"
+type Problem struct {
+	Field   string
+	Message string
+}
+
+func (p Emitter) Problems() []Problem {
+	problems := make([]Problem, 0)
+	p.collectProblems("", &problems)
+	return problems
+}
+
+func (p Emitter) collectProblems(prefix string, problems *[]Problem) {
+	add := func(field, format string, args ...interface{}) {
+		*problems = append(*problems, Problem{prefix + field, fmt.Sprintf(format, args...)})
+	}
+	switch p.Type {
+	case Radial, Spiral, Aimed, Wall:
+	default:
+		add("type", "unknown pattern type %q, expected radial, spiral, aimed or wall", p.Type)
+	}
+	if p.Count <= 0 {
+		add("count", "pattern needs at least one bullet per shot, got %d", p.Count)
+	}
+	if p.Radius <= 0 {
+		add("radius", "bullet radius must be positive, got %g", p.Radius)
+	}
+	if p.Delay < 0 {
+		add("delay", "pattern delay can't be negative, got %d", p.Delay)
+	}
+	if p.Interval <= 0 {
+		add("interval", "pattern interval must be at least one tick, got %d", p.Interval)
+	}
+	if p.Repeat < 0 {
+		add("repeat", "pattern repeat can't be negative, got %d", p.Repeat)
+	}
+	if p.Lifetime < 0 {
+		add("lifetime", "bullet lifetime can't be negative, got %d", p.Lifetime)
+	}
+	for k, child := range p.Children {
+		child.collectProblems(fmt.Sprintf("%schildren[%d].", prefix, k), problems)
+	}
+}
+
+func (p Emitter) Validate() error {
+	if problems := p.Problems(); len(problems) > 0 {
+		return fmt.Errorf("%s: %s", problems[0].Field, problems[0].Message)
+	}
+	return nil
+}
+
+// bullets for the nth shot of the pattern from an origin, aimed shots go at the target
+func (p Emitter) Spawns(shot int, originX, originY, targetX, targetY float64) []Spawn {
+	return p.AppendSpawns(make([]Spawn, 0, p.Count), shot, originX, originY, targetX, targetY)
+}
+
+// same as Spawns but adds them onto the end of spawns, so a buffer can be reused between shots
+func (p Emitter) AppendSpawns(spawns []Spawn, shot int, originX, originY, targetX, targetY float64) []Spawn {
+	add := func(x, y, angle float64) {
+		vx, vy := DirectionVector(angle, p.Speed)
+		ax, ay := DirectionVector(angle, p.Acceleration)
+		spawns = append(spawns, Spawn{x, y, vx, vy, ax, ay})
+	}
+
+	switch p.Type {
+	case Radial, Spiral:
+		start := p.Angle
+		if p.Type == Spiral {
+			start += p.AngleStep * float64(shot)
+		}
+		for i := 0; i < p.Count; i++ {
+			add(originX, originY, start+float64(i)/float64(p.Count))
+		}
+	case Aimed:
+		aim := AngleTo(originX, originY, targetX, targetY)
+		if p.Count == 1 {
+			add(originX, originY, aim)
+			break
+		}
+		for i := 0; i < p.Count; i++ {
+			add(originX, originY, aim-p.Spread/2+p.Spread*float64(i)/float64(p.Count-1))
+		}
+	case Wall:
+		// line is centered on the origin, perpendicular to travel
+		px, py := DirectionVector(p.Angle+0.25, p.Spread)
+		mid := float64(p.Count-1) / 2
+		for i := 0; i < p.Count; i++ {
+			offset := float64(i) - mid
+			add(originX+px*offset, originY+py*offset, p.Angle)
+		}
+	}
+	return spawns
+}
+
+// angles are in turns like everywhere else, 0 is pointing right and they go clockwise (y is down)
+func DirectionVector(angle, length float64) (x, y float64) {
+	return length * math.Cos(2*math.Pi*angle), length * math.Sin(2*math.Pi*angle)
+}
+
+// angle in turns from one point to another
+func AngleTo(fromX, fromY, toX, toY float64) float64 {
+	return math.Atan2(toY-fromY, toX-fromX) / (2 * math.Pi)
+}